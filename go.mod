@@ -7,11 +7,14 @@ require (
 	github.com/baiyubin/aliyun-sts-go-sdk v0.0.0-20180326062324-cfa1a18b161f // indirect
 	github.com/gin-contrib/cors v1.3.1
 	github.com/gin-gonic/gin v1.7.0
+	github.com/go-git/go-git/v5 v5.4.2
 	github.com/jlaffaye/ftp v0.0.0-20200309171336-6841a2daa0d5
 	github.com/json-iterator/go v1.1.9
+	github.com/robfig/cron/v3 v3.0.1
 	github.com/satori/go.uuid v1.2.0 // indirect
+	go.etcd.io/bbolt v1.3.5
 	golang.org/x/time v0.0.0-20191024005414-555d28b269f0 // indirect
-	gopkg.in/yaml.v2 v2.2.8
+	gopkg.in/yaml.v2 v2.3.0
 	k8s.io/apimachinery v0.17.3
 	k8s.io/client-go v0.17.0
 	k8s.io/klog v1.0.0
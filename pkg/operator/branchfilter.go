@@ -0,0 +1,131 @@
+package operator
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"k8s.io/klog"
+)
+
+const (
+	errBranchPattern        = "the branch pattern `%s` is invalid err:%v"
+	errGitBranchFilteredOut = "the branch name `%s` of the git was filtered out"
+	errGitBranchProtected   = "the branch name `%s` of the git is protected"
+)
+
+// branchPattern is a glob, or a regular expression if it was wrapped by the slashes
+type branchPattern struct {
+	glob string
+	re   *regexp.Regexp
+}
+
+func newBranchPattern(s string) (bp branchPattern, err error) {
+	if len(s) > 1 && strings.HasPrefix(s, "/") && strings.HasSuffix(s, "/") {
+		re, err := regexp.Compile(s[1 : len(s)-1])
+		if err != nil {
+			return bp, errors.New(fmt.Sprintf(errBranchPattern, s, err))
+		}
+		return branchPattern{re: re}, nil
+	}
+	if _, err := path.Match(s, ""); err != nil {
+		return bp, errors.New(fmt.Sprintf(errBranchPattern, s, err))
+	}
+	return branchPattern{glob: s}, nil
+}
+
+func (bp branchPattern) Match(name string) bool {
+	if bp.re != nil {
+		return bp.re.MatchString(name)
+	}
+	matched, _ := path.Match(bp.glob, name)
+	return matched
+}
+
+type branchMeta struct {
+	branchPattern
+	BranchMeta
+}
+
+// branchFilter applies the BranchesConfig, the invalid patterns were ignored
+type branchFilter struct {
+	include []branchPattern
+	exclude []branchPattern
+	meta    []branchMeta
+}
+
+func newBranchPatterns(patterns []string) []branchPattern {
+	res := make([]branchPattern, 0, len(patterns))
+	for _, v := range patterns {
+		bp, err := newBranchPattern(v)
+		if err != nil {
+			klog.V(2).Info(err)
+			continue
+		}
+		res = append(res, bp)
+	}
+	return res
+}
+
+func newBranchFilter(conf BranchesConfig) *branchFilter {
+	bf := &branchFilter{
+		include: newBranchPatterns(conf.Include),
+		exclude: newBranchPatterns(conf.Exclude),
+		meta:    make([]branchMeta, 0, len(conf.Meta)),
+	}
+	for _, v := range conf.Meta {
+		bp, err := newBranchPattern(v.Pattern)
+		if err != nil {
+			klog.V(2).Info(err)
+			continue
+		}
+		bf.meta = append(bf.meta, branchMeta{branchPattern: bp, BranchMeta: v})
+	}
+	return bf
+}
+
+func matchAny(patterns []branchPattern, name string) bool {
+	for _, v := range patterns {
+		if v.Match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allowed returns true if the branch was included and not excluded
+func (bf *branchFilter) Allowed(name string) bool {
+	if len(bf.include) > 0 && !matchAny(bf.include, name) {
+		return false
+	}
+	return !matchAny(bf.exclude, name)
+}
+
+// Meta returns the metadata of the first matched pattern
+func (bf *branchFilter) Meta(name string) (m BranchMeta, ok bool) {
+	for _, v := range bf.meta {
+		if v.Match(name) {
+			return v.BranchMeta, true
+		}
+	}
+	return m, false
+}
+
+// CheckBranch returns an error if the branch was filtered out, or it was protected but was going to be pushed
+func (g *git) CheckBranch(name string, push bool) error {
+	if !g.filter.Allowed(name) {
+		return errors.New(fmt.Sprintf(errGitBranchFilteredOut, name))
+	}
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	b, ok := g.RemoteBranches[name]
+	if !ok {
+		return errors.New(fmt.Sprintf(errGitBranchWasNotExisted, name))
+	}
+	if push && b.Protected {
+		return errors.New(fmt.Sprintf(errGitBranchProtected, name))
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package operator
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_branchFilter(t *testing.T) {
+	bf := newBranchFilter(BranchesConfig{
+		Include: []string{"master", "release/*", `/^hotfix-\d+$/`},
+		Exclude: []string{"release/old*", "/[/"},
+		Meta: []BranchMeta{
+			{Pattern: "master", DisplayName: "Trunk", Protected: true},
+			{Pattern: "release/*", DisplayName: "Release"},
+		},
+	})
+	tests := []struct {
+		branch string
+		want   bool
+	}{
+		{branch: "master", want: true},
+		{branch: "release/1.2", want: true},
+		{branch: "release/old-1.0", want: false},
+		{branch: "hotfix-12", want: true},
+		{branch: "hotfix-a", want: false},
+		{branch: "feature/a", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			if got := bf.Allowed(tt.branch); got != tt.want {
+				t.Errorf("Allowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if m, ok := bf.Meta("master"); !ok || m.DisplayName != "Trunk" || !m.Protected {
+		t.Errorf("Meta() = %v, %v, want the protected Trunk", m, ok)
+	}
+}
+
+func Test_git_CheckBranch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	g := NewGitOperator(&ProjectConfig{
+		ProjectName: "projectName",
+		Git: GitConfig{Branches: BranchesConfig{
+			Exclude: []string{"feature/*"},
+			Meta:    []BranchMeta{{Pattern: "master", Protected: true}},
+		}},
+	}, NewMemoryStore(), ctx).(*git)
+	g.mu.Lock()
+	g.backend = &scriptBackend{exec: func(ctx context.Context, args ...string) (res []byte, err error) {
+		if args[0] == cmdGitShowAll {
+			return []byte("* master\n  remotes/origin/master\n  remotes/origin/dev\n  remotes/origin/feature/a\n"), nil
+		}
+		return res, nil
+	}}
+	g.mu.Unlock()
+	if err := g.ShowAll(true); err != nil {
+		t.Fatal(err)
+	}
+	if gi := g.GetGitInfo(); len(gi.ListBranches) != 2 {
+		t.Errorf("GetGitInfo() = %v, want master and dev", gi.ListBranches)
+	}
+	if err := g.CheckBranch("feature/a", false); err == nil {
+		t.Errorf("CheckBranch() error = nil for the excluded branch")
+	}
+	if err := g.CheckBranch("master", true); err == nil {
+		t.Errorf("CheckBranch() error = nil for pushing the protected branch")
+	}
+	if err := g.CheckBranch("master", false); err != nil {
+		t.Errorf("CheckBranch() error = %v, want nil", err)
+	}
+}
+
+func Test_project_checkCommandBranch(t *testing.T) {
+	conf := ProjectConfig{
+		ProjectName: "projectName",
+		Git: GitConfig{Branches: BranchesConfig{
+			Exclude: []string{"feature/*"},
+			Meta:    []BranchMeta{{Pattern: "master", Protected: true}},
+		}},
+		Pipelines: []PipelineConfig{
+			{Name: "publish", Steps: []PipelineStep{{Command: TaskCmdSvnCommit}, {Command: TaskCmdFtpUpload}}},
+			{Name: "release", Steps: []PipelineStep{{Command: TaskCmdGitGen}, {Command: TaskCmdFtpUpload}}},
+		},
+	}
+	g := NewGitOperator(&conf, NewMemoryStore(), context.Background()).(*git)
+	g.RemoteBranches = map[string]*Branch{
+		"master":    {Name: "master", Protected: true},
+		"dev":       {Name: "dev"},
+		"feature/a": {Name: "feature/a"},
+	}
+	p := &project{conf: conf, git: g}
+	tests := []struct {
+		command  Command
+		rejected bool
+	}{
+		{Command{Command: TaskCmdGitGen, BranchName: "dev"}, false},
+		{Command{Command: TaskCmdGitGen, BranchName: "master"}, true},
+		{Command{Command: TaskCmdFtpUpload, BranchName: "master"}, false},
+		{Command{Command: TaskCmdFtpUpload, BranchName: "feature/a"}, true},
+		{Command{Command: TaskCmdSvnCommit, BranchName: "missing"}, true},
+		{Command{Command: TaskCmdSvnRollback, Revision: "12"}, false},
+		{Command{Command: TaskCmdPipeline, Pipeline: "publish", BranchName: "master"}, false},
+		{Command{Command: TaskCmdPipeline, Pipeline: "release", BranchName: "master"}, true},
+	}
+	for _, tt := range tests {
+		if err := p.checkCommandBranch(&tt.command); (err != nil) != tt.rejected {
+			t.Errorf("checkCommandBranch(%s %s) error = %v, rejected %v", tt.command.Command, tt.command.BranchName, err, tt.rejected)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"sync"
+)
+
+// execute runs the command in its own process group, and the whole group would be killed once the ctx was done,
+// so that the children forked by the scripts (e.g. git, svn, zip) would not be left behind.
+// The stdout and stderr would be streamed line by line into the task bound to the ctx if there was one,
+// and the execution would be recorded in the task with the redacted args.
+func execute(ctx context.Context, name string, args ...string) (out []byte, err error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	setProcessGroup(cmd)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	t, ok := taskFromContext(ctx)
+	if ok {
+		i := t.beginExecution(name, redact(args, redactedFromContext(ctx)))
+		emit := func(line string) {
+			t.appendExecutionOutput(i, line)
+		}
+		o := newLineWriter(emit)
+		e := newLineWriter(emit)
+		cmd.Stdout = io.MultiWriter(&stdout, o)
+		cmd.Stderr = e
+		defer func() {
+			o.Flush()
+			e.Flush()
+			t.endExecution(i, exitCode(cmd, err), err)
+		}()
+	}
+	if err = cmd.Start(); err != nil {
+		return out, err
+	}
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			killProcessGroup(cmd)
+		case <-done:
+		}
+	}()
+	err = cmd.Wait()
+	close(done)
+	if err != nil && ctx.Err() != nil {
+		return stdout.Bytes(), ctx.Err()
+	}
+	return stdout.Bytes(), err
+}
+
+// exitCode returns -1 if the process was not started or was killed by a signal
+func exitCode(cmd *exec.Cmd, err error) int {
+	if cmd.ProcessState != nil {
+		return cmd.ProcessState.ExitCode()
+	}
+	if err != nil {
+		return -1
+	}
+	return 0
+}
+
+const redactedArg = "******"
+
+type redactedContextKey struct{}
+
+// withRedacted declares the secrets which would be redacted from the args recorded in the task
+func withRedacted(ctx context.Context, secrets ...string) context.Context {
+	return context.WithValue(ctx, redactedContextKey{}, append(redactedFromContext(ctx), secrets...))
+}
+
+func redactedFromContext(ctx context.Context) []string {
+	secrets, _ := ctx.Value(redactedContextKey{}).([]string)
+	return secrets
+}
+
+func redact(args, secrets []string) []string {
+	res := make([]string, 0, len(args))
+	for _, v := range args {
+		for _, secret := range secrets {
+			if secret != "" && v == secret {
+				v = redactedArg
+				break
+			}
+		}
+		res = append(res, v)
+	}
+	return res
+}
+
+// appendTaskMessage appends the message to the task bound to the ctx
+func appendTaskMessage(ctx context.Context, msg string) {
+	if t, ok := taskFromContext(ctx); ok {
+		t.appendOutput(msg)
+	}
+}
+
+// lineWriter splits the written bytes into lines and emits them one by one
+type lineWriter struct {
+	mu   sync.Mutex
+	buf  []byte
+	emit func(line string)
+}
+
+func newLineWriter(emit func(line string)) *lineWriter {
+	return &lineWriter{
+		buf:  make([]byte, 0),
+		emit: emit,
+	}
+}
+
+func (lw *lineWriter) Write(p []byte) (n int, err error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	lw.buf = append(lw.buf, p...)
+	for {
+		i := bytes.IndexByte(lw.buf, '\n')
+		if i < 0 {
+			break
+		}
+		lw.emit(string(bytes.TrimRight(lw.buf[:i], "\r")))
+		lw.buf = lw.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush emits the last line which was not terminated by a newline
+func (lw *lineWriter) Flush() {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	if len(lw.buf) > 0 {
+		lw.emit(string(lw.buf))
+		lw.buf = lw.buf[:0]
+	}
+}
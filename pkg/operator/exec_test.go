@@ -0,0 +1,84 @@
+package operator
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_execute(t *testing.T) {
+	out, err := execute(context.Background(), "sh", "-c", "echo go-gpt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "go-gpt\n" {
+		t.Errorf("execute() = %q, want %q", out, "go-gpt\n")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(time.Millisecond*100, cancel)
+	start := time.Now()
+	// the child sleep holds the stdout, so execute would not return unless the whole process group was killed
+	_, err = execute(ctx, "sh", "-c", "sleep 30 & wait")
+	if err != context.Canceled {
+		t.Errorf("execute() error = %v, want %v", err, context.Canceled)
+	}
+	if d := time.Since(start); d > time.Second*5 {
+		t.Errorf("execute() returned after %v, the process group was not killed", d)
+	}
+}
+
+func Test_execute_withTask(t *testing.T) {
+	th := NewTaskHub(NewMemoryStore(), context.Background())
+	task := th.NewTask(&Command{ProjectName: "p", Command: TaskCmdGitGen})
+	history, ch, unsubscribe := task.Subscribe()
+	defer unsubscribe()
+	if len(history) != 0 {
+		t.Errorf("Subscribe() history = %v, want empty", history)
+	}
+	out, err := execute(withTask(task.ctx, task), "sh", "-c", "echo line1; echo line2 >&2; printf line3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "line1\nline3" {
+		t.Errorf("execute() = %q, want %q", out, "line1\nline3")
+	}
+	got := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		got[<-ch] = true
+	}
+	for _, v := range []string{"line1", "line2", "line3"} {
+		if !got[v] {
+			t.Errorf("the subscriber did not receive %q, got %v", v, got)
+		}
+	}
+	task.ChangeStatus(TaskCompleted)
+	for range ch {
+	}
+	if len(task.Message) != 4 {
+		t.Errorf("Task.Message = %v, want 3 output lines and 1 status message", task.Message)
+	}
+}
+
+func Test_execute_execution(t *testing.T) {
+	th := NewTaskHub(NewMemoryStore(), context.Background())
+	task := th.NewTask(&Command{ProjectName: "p", Command: TaskCmdSvnCommit})
+	ctx := withRedacted(withTask(task.ctx, task), "secret")
+	if _, err := execute(ctx, "sh", "-c", "echo out; exit 3", "secret"); err == nil {
+		t.Fatalf("execute() error = nil, want the exit error")
+	}
+	got := task.snapshot().Executions
+	if len(got) != 1 {
+		t.Fatalf("Executions = %v, want 1", got)
+	}
+	e := got[0]
+	if e.ExitCode != 3 || e.Error == "" || e.FinishedAt.IsZero() {
+		t.Errorf("Execution = %+v, want the exit code 3", e)
+	}
+	if e.Args[2] != redactedArg {
+		t.Errorf("Execution.Args = %v, want the secret redacted", e.Args)
+	}
+	if len(e.Output) != 1 || e.Output[0] != "out" {
+		t.Errorf("Execution.Output = %v, want [out]", e.Output)
+	}
+}
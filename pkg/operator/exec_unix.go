@@ -0,0 +1,24 @@
+//go:build !windows
+// +build !windows
+
+package operator
+
+import (
+	"os/exec"
+	"syscall"
+
+	"k8s.io/klog"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func killProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL); err != nil {
+		klog.V(2).Infof("kill process group pid:%d err:%v", cmd.Process.Pid, err)
+	}
+}
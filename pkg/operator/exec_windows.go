@@ -0,0 +1,21 @@
+//go:build windows
+// +build windows
+
+package operator
+
+import (
+	"os/exec"
+
+	"k8s.io/klog"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func killProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	if err := cmd.Process.Kill(); err != nil {
+		klog.V(2).Infof("kill process pid:%d err:%v", cmd.Process.Pid, err)
+	}
+}
@@ -2,9 +2,11 @@ package operator
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"regexp"
 	"strconv"
@@ -21,7 +23,7 @@ type FtpOperator interface {
 	List(filter string) (res []Entry, err error)
 	ReadFileContent(fileName string) (res []byte, err error)
 	WriteFileContent(fileName string, content []byte) (err error)
-	UploadFile(sourcePath, fileName string) (err error)
+	UploadFile(ctx context.Context, sourcePath, fileName string) (err error)
 	GetNextVersion() (version string, err error)
 }
 
@@ -49,7 +51,7 @@ type Entry struct {
 func (f *ftp) Conn() (c *goftp.ServerConn, err error) {
 	c, err = goftp.Dial(fmt.Sprintf("%s:%d", f.conf.Host, f.conf.Port), goftp.DialWithTimeout(time.Duration(f.conf.Timeout)*time.Second))
 	if err != nil {
-		return c, errors.New(fmt.Sprintf(errFtpConnDial, err))
+		return c, newTaskError(ErrorKindNetwork, fmt.Sprintf(errFtpConnDial, err))
 	}
 	err = c.Login(f.conf.Username, f.conf.Password)
 	if err != nil {
@@ -58,6 +60,59 @@ func (f *ftp) Conn() (c *goftp.ServerConn, err error) {
 	return c, nil
 }
 
+// connContext is the Conn whose connections, including the data ones, would be closed once the ctx was done,
+// so that a hung transfer would be interrupted by the timeout or the cancellation of the task.
+// The stop must be called after the connection was quit
+func (f *ftp) connContext(ctx context.Context) (c *goftp.ServerConn, stop func(), err error) {
+	var mu sync.Mutex
+	conns := make([]net.Conn, 0)
+	closed := false
+	quit := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			mu.Lock()
+			defer mu.Unlock()
+			closed = true
+			for _, v := range conns {
+				if err := v.Close(); err != nil {
+					klog.V(2).Info(err)
+				}
+			}
+		case <-quit:
+		}
+	}()
+	stop = func() {
+		close(quit)
+	}
+	dialer := net.Dialer{Timeout: time.Duration(f.conf.Timeout) * time.Second}
+	dial := func(network, address string) (net.Conn, error) {
+		conn, err := dialer.DialContext(ctx, network, address)
+		if err != nil {
+			return conn, err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			conn.Close()
+			return nil, ctx.Err()
+		}
+		conns = append(conns, conn)
+		return conn, nil
+	}
+	c, err = goftp.Dial(fmt.Sprintf("%s:%d", f.conf.Host, f.conf.Port), goftp.DialWithDialFunc(dial))
+	if err != nil {
+		stop()
+		return c, nil, newTaskError(ErrorKindNetwork, fmt.Sprintf(errFtpConnDial, err))
+	}
+	if err = c.Login(f.conf.Username, f.conf.Password); err != nil {
+		f.Quit(c)
+		stop()
+		return c, nil, errors.New(fmt.Sprintf(errFtpLogin, err))
+	}
+	return c, stop, nil
+}
+
 func (f *ftp) Quit(c *goftp.ServerConn) {
 	if err := c.Quit(); err != nil {
 		klog.V(2).Info(errQuitErr, err)
@@ -127,16 +182,18 @@ func (f *ftp) WriteFileContent(fileName string, content []byte) (err error) {
 	return nil
 }
 
-func (f *ftp) UploadFile(sourcePath, fileName string) (err error) {
-	c, err := f.Conn()
+func (f *ftp) UploadFile(ctx context.Context, sourcePath, fileName string) (err error) {
+	file, err := os.Open(sourcePath)
 	if err != nil {
 		return err
 	}
-	defer f.Quit(c)
-	file, err := os.Open(sourcePath)
+	defer file.Close()
+	c, stop, err := f.connContext(ctx)
 	if err != nil {
-		klog.V(2).Info(err)
+		return err
 	}
+	defer stop()
+	defer f.Quit(c)
 	if err = c.Stor(fmt.Sprintf("%s/%s", f.conf.WorkDir, fileName), file); err != nil {
 		return err
 	}
@@ -146,7 +203,8 @@ func (f *ftp) UploadFile(sourcePath, fileName string) (err error) {
 func (f *ftp) GetNextVersion() (version string, err error) {
 	res, err := f.List("")
 	if err != nil {
-		return GetNextVersionString(0), errors.New(fmt.Sprintf("List err:%v", err))
+		// the err was returned as it was, so that the kind of the dial error would be kept for the retry
+		return GetNextVersionString(0), err
 	}
 	v := GetTodayVersionByFilter(res, "introduce", "txt")
 	return GetNextVersionString(v), nil
@@ -1,8 +1,14 @@
 package operator
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
+	"net"
+	"net/textproto"
+	"os"
 	"reflect"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -78,3 +84,83 @@ func Test_ftp_Quit(t *testing.T) {
 	}
 }
 
+
+func Test_ftp_GetNextVersion_dialError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+	conf := fakeFtpConfig
+	conf.Port = port
+	conf.Timeout = 1
+	_, err = NewFtpOperator(conf).GetNextVersion()
+	if err == nil {
+		t.Fatal("GetNextVersion() = nil, want the dial error")
+	}
+	if kind := errorKind(err); kind != ErrorKindNetwork {
+		t.Errorf("errorKind() = %s, want %s", kind, ErrorKindNetwork)
+	}
+	if !(RetryConfig{MaxAttempts: 2}).Retryable(err) {
+		t.Errorf("Retryable() = false, want the dial error to be retried by default")
+	}
+}
+
+// hungFtpServer accepts the login, and never replies to the following commands
+func hungFtpServer(t *testing.T) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		proto := textproto.NewConn(conn)
+		proto.PrintfLine("220 hung")
+		for {
+			line, err := proto.ReadLine()
+			if err != nil {
+				return
+			}
+			switch strings.SplitN(line, " ", 2)[0] {
+			case "USER":
+				proto.PrintfLine("331 password")
+			case "PASS":
+				proto.PrintfLine("230 logged in")
+			}
+		}
+	}()
+	return l
+}
+
+func Test_ftp_UploadFile_cancelled(t *testing.T) {
+	l := hungFtpServer(t)
+	defer l.Close()
+	file, err := ioutil.TempFile("", "go-gpt-ftp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+	conf := fakeFtpConfig
+	conf.Port = l.Addr().(*net.TCPAddr).Port
+	conf.Timeout = 1
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*200)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- NewFtpOperator(conf).UploadFile(ctx, file.Name(), "server.zip")
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("UploadFile() = nil, want the error of the closed connection")
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("UploadFile() was not interrupted by the ctx")
+	}
+}
@@ -4,8 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os/exec"
-	"regexp"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -17,6 +15,12 @@ import (
 type GitCmd struct {
 	cmd        string
 	branchName string
+	// done receives the result of the command if it was not nil
+	done chan error
+}
+
+func (c *GitCmd) String() string {
+	return strings.TrimSpace(fmt.Sprintf("%s %s", c.cmd, c.branchName))
 }
 
 type GitOperator interface {
@@ -25,22 +29,31 @@ type GitOperator interface {
 	RUnlock()
 	GetBranchFullName(name string) string
 	GetBranchShortName(name string) string
-	ExecuteWithArgs(args ...string) (res []byte, err error)
+	ExecuteWithArgs(ctx context.Context, args ...string) (res []byte, err error)
 	FetchAll() error
 	Revert() (err error)
+	WorkDir(ctx context.Context) string
+	Checkout(ctx context.Context, name string) (wctx context.Context, release func(), err error)
 	ShowAll(lock bool) error
-	CheckOutBranch(name string) error
-	Generate(name string) error
-	Commit(name string) error
-	Push(name string) error
-	Update(name string) error
-	Common(name string) error
+	CheckOutBranch(ctx context.Context, name string) error
+	Log(ctx context.Context, ref string, limit int) (res []Commit, err error)
+	Diff(ctx context.Context, from, to string) (res []FileChange, err error)
+	Generate(ctx context.Context, name string) error
+	Commit(ctx context.Context, name string) error
+	Push(ctx context.Context, name string) error
+	Update(ctx context.Context, name string) error
+	Common(ctx context.Context, name string) error
+	CheckBranch(name string, push bool) error
 	SetSvnTag(name, tag string) error
-	SvnSync(name, svnWorkDir string) error
-	FtpCompress(name, patchType, version, flags string) error
+	DeleteSvnTag(name string) error
+	SvnTags() []SvnTagMapping
+	SvnSync(ctx context.Context, name, svnWorkDir string) error
+	FtpCompress(ctx context.Context, name, patchType, version, flags string) (workDir string, release func(), err error)
 	ChangeTaskCount(incr int32)
 	LoopChan()
 	SendCommand(c *GitCmd) (err error)
+	Refresh() error
+	RefreshSync(ctx context.Context) error
 	HandleCommand(c *GitCmd) error
 	GetGitInfo() GitInfo
 }
@@ -52,6 +65,7 @@ const (
 	errWriteToChannelTimeout  = "the command `%v` writes to channel time out"
 
 	execOutputTemplate = "Git Command `%s` output:\n%s\n"
+	execHeaderTemplate = "$ %s %s"
 )
 
 const (
@@ -65,12 +79,18 @@ const (
 	cmdGitFetchAll = "fetch"
 	cmdGitRevert   = "revert"
 	cmdGitShowAll  = "showAll"
+	cmdGitHeads    = "heads"
+	cmdGitLog      = "log"
 	cmdGitGenerate = "generate"
 	cmdGitCommit   = "commit"
 	cmdGitPush     = "push"
+	cmdGitPushHead = "pushHead"
 	cmdGitUpdate   = "update"
 	cmdSvnSync     = "svnSync"
 	cmdFtpCompress = "compress"
+
+	cmdGitWorktreeAdd    = "worktreeAdd"
+	cmdGitWorktreeRemove = "worktreeRemove"
 )
 
 const (
@@ -80,8 +100,16 @@ const (
 
 type git struct {
 	mu sync.RWMutex
+	// workDirMu serializes the refresh and the tasks using the shared WorkDir,
+	// the tasks hold it from the Checkout until the release unless GitConfig.Worktree was enabled
+	workDirMu sync.Mutex
 
-	conf GitConfig
+	conf    GitConfig
+	backend gitBackend
+	store   Store
+	filter  *branchFilter
+	// svnTags are the tags which were set to the branches explicitly
+	svnTags map[string]string
 
 	ScriptPath string `json:"script_path"`
 
@@ -90,8 +118,13 @@ type git struct {
 	ListBranches   []string           `json:"list_branches"`
 	TaskCount      int32              `json:"task_count"`
 	TaskChan       chan *GitCmd
-	CurrentTask    *GitCmd
 	ctx            context.Context
+
+	// statusMu guards the status of the LoopChan which was shown in the GitInfo
+	statusMu    sync.Mutex
+	currentTask *GitCmd
+	lastRefresh time.Time
+	lastError   error
 }
 
 func (g *git) Conf() GitConfig {
@@ -114,13 +147,14 @@ func (g *git) GetBranchShortName(name string) string {
 	return strings.Replace(name, remoteBranchPrefix, "", -1)
 }
 
-func (g *git) ExecuteWithArgs(args ...string) (res []byte, err error) {
-	t := append([]string{g.ScriptPath, g.conf.WorkDir}, args...)
-	out, err := exec.Command("sh", t...).Output()
+func (g *git) ExecuteWithArgs(ctx context.Context, args ...string) (res []byte, err error) {
+	t := append([]string{g.ScriptPath, g.WorkDir(ctx)}, args...)
+	appendTaskMessage(ctx, fmt.Sprintf(execHeaderTemplate, gitScriptName, strings.Join(args, " ")))
+	out, err := execute(ctx, "sh", t...)
 	if err != nil {
-		return out, errors.New(fmt.Sprintf(errGitExec, args[0], err))
+		return out, newExecError(err, fmt.Sprintf(errGitExec, args[0], err))
 	}
-	if args[0] != cmdGitShowAll {
+	if args[0] != cmdGitShowAll && args[0] != cmdGitHeads && args[0] != cmdGitLog {
 		klog.Infof(execOutputTemplate, args[0], string(out))
 	}
 	return out, nil
@@ -129,16 +163,16 @@ func (g *git) ExecuteWithArgs(args ...string) (res []byte, err error) {
 func (g *git) FetchAll() (err error) {
 	//g.mu.Lock()
 	//defer g.mu.Unlock()
-	_, err = g.ExecuteWithArgs(cmdGitFetchAll)
-	if err != nil {
-		return err
-	}
-	return nil
+	return g.backend.Fetch(g.ctx)
 }
 
 func (g *git) Revert() (err error) {
-	_, err = g.ExecuteWithArgs(cmdGitRevert)
-	if err != nil {
+	// the revert is the cleanup of the other commands, so it should not be stopped by their contexts
+	return g.revert(g.ctx)
+}
+
+func (g *git) revert(ctx context.Context) (err error) {
+	if err = g.backend.Revert(ctx); err != nil {
 		klog.V(2).Info(err)
 		return err
 	}
@@ -150,175 +184,174 @@ func (g *git) ShowAll(lock bool) (err error) {
 		g.mu.Lock()
 		defer g.mu.Unlock()
 	}
-	out, err := g.ExecuteWithArgs(cmdGitShowAll)
+	branches, activeBranch, err := g.backend.Branches(g.ctx)
 	if err != nil {
 		return err
 	}
-	arr := strings.Split(string(out), "\n")
-	if len(arr) == 0 {
-		return errors.New(fmt.Sprintf("ShowAll len == 0 name:%s out:%v\n", g.Name, out))
+	if len(branches) == 0 {
+		return errors.New(fmt.Sprintf("ShowAll len == 0 name:%s\n", g.Name))
 	}
 	tmp := make(map[string]*Branch, 0)
 	g.ListBranches = make([]string, 0)
-	var activeBranch string
-	for _, v := range arr {
-		if v == "" {
+	for _, v := range branches {
+		s := v.Name
+		if !g.filter.Allowed(s) {
 			continue
 		}
-		v = strings.Replace(v, " ", "", -1)
-		s := strings.Replace(v, "*", "", -1)
-		activeMatched, err := regexp.Match(`\*`, []byte(v))
-		if err != nil {
-			return errors.New(fmt.Sprintf("ShowAll regexp.Match active name:%s err:%v\n", g.Name, err))
-		}
-		if activeMatched == true {
-			activeBranch = s
-		}
-		if matched, err := regexp.Match(`remotes`, []byte(v)); err != nil {
-			return errors.New(fmt.Sprintf("ShowAll regexp.Match local/remote name:%s err:%v\n", g.Name, err))
+		meta, _ := g.filter.Meta(s)
+		if t, ok := g.RemoteBranches[s]; ok {
+			if s == activeBranch {
+				t.Active = gitActive
+			} else {
+				t.Active = gitInActive
+			}
+			t.Head = v.Head
+			t.SvnTag, _ = g.svnTag(s)
+			t.DisplayName, t.Protected = meta.DisplayName, meta.Protected
+			tmp[s] = t
 		} else {
-			if matched == false {
-				continue
+			b := &Branch{
+				Name:        s,
+				Active:      gitInActive,
+				Head:        v.Head,
+				DisplayName: meta.DisplayName,
+				Protected:   meta.Protected,
 			}
-			s = g.GetBranchShortName(s)
-			if t, ok := g.RemoteBranches[s]; ok {
-				if s == activeBranch {
-					t.Active = gitActive
-				} else {
-					t.Active = gitInActive
-				}
-				tmp[s] = t
-			} else {
-				b := &Branch{
-					Name:   s,
-					Active: gitInActive,
-					SvnTag: "",
-				}
-				if s == activeBranch {
-					b.Active = gitActive
-				}
-				tmp[s] = b
+			b.SvnTag, _ = g.svnTag(s)
+			if s == activeBranch {
+				b.Active = gitActive
 			}
-			g.ListBranches = append(g.ListBranches, s)
+			tmp[s] = b
 		}
+		g.ListBranches = append(g.ListBranches, s)
 	}
 	g.RemoteBranches = tmp
 	return nil
 }
 
-func (g *git) CheckOutBranch(name string) (err error) {
-	_, ok := g.RemoteBranches[name]
+// branch returns a copy of the remote branch, the lock was held only for the lookup,
+// the tasks and the refresh using the shared WorkDir were serialized by the workDirMu instead
+func (g *git) branch(name string) (b Branch, ok bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	t, ok := g.RemoteBranches[name]
 	if !ok {
-		return errors.New(fmt.Sprintf(errGitBranchWasNotExisted, name))
+		return b, false
 	}
-	_, err = g.ExecuteWithArgs(cmdGitCheckOut, name, g.GetBranchFullName(name))
-	if err != nil {
-		return err
+	return *t, true
+}
+
+func (g *git) CheckOutBranch(ctx context.Context, name string) (err error) {
+	if _, ok := g.branch(name); !ok {
+		return errors.New(fmt.Sprintf(errGitBranchWasNotExisted, name))
 	}
-	return nil
+	return g.backend.Checkout(ctx, name)
+}
+
+func (g *git) Log(ctx context.Context, ref string, limit int) (res []Commit, err error) {
+	return g.backend.Log(ctx, ref, limit)
+}
+
+func (g *git) Diff(ctx context.Context, from, to string) (res []FileChange, err error) {
+	return g.backend.Diff(ctx, from, to)
 }
 
-func (g *git) Generate(name string) (err error) {
-	_, err = g.ExecuteWithArgs(cmdGitGenerate, name)
+func (g *git) Generate(ctx context.Context, name string) (err error) {
+	_, err = g.ExecuteWithArgs(ctx, cmdGitGenerate, name)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (g *git) Commit(name string) (err error) {
-	_, err = g.ExecuteWithArgs(cmdGitCommit, name)
+func (g *git) Commit(ctx context.Context, name string) (err error) {
+	_, err = g.ExecuteWithArgs(ctx, cmdGitCommit, name)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (g *git) Push(name string) (err error) {
-	_, err = g.ExecuteWithArgs(cmdGitPush, name)
+func (g *git) Push(ctx context.Context, name string) (err error) {
+	if _, ok := workDirFromContext(ctx); ok {
+		// only the HEAD of the temporary branch in the worktree should be pushed
+		_, err = g.ExecuteWithArgs(ctx, cmdGitPushHead, name)
+	} else {
+		_, err = g.ExecuteWithArgs(ctx, cmdGitPush, name)
+	}
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (g *git) Common(name string) (err error) {
+func (g *git) Common(ctx context.Context, name string) (err error) {
 	//if err = g.ShowAll(true); err != nil {
 	//	return err
 	//}
 	//g.mu.RLock()
 	//defer g.mu.RUnlock()
-	defer func() {
-		if err := g.Revert(); err != nil {
-			klog.V(2).Info(err)
-		}
-	}()
-	if err = g.CheckOutBranch(name); err != nil {
+	ctx, release, err := g.Checkout(ctx, name)
+	if err != nil {
 		return err
 	}
-	if err = g.Generate(name); err != nil {
+	defer release()
+	if err = g.Generate(ctx, name); err != nil {
 		return err
 	}
-	if err = g.Commit(name); err != nil {
+	if err = g.Commit(ctx, name); err != nil {
 		return err
 	}
-	if err = g.Push(name); err != nil {
+	if err = g.Push(ctx, name); err != nil {
 		return err
 	}
-	return nil
-}
-
-func (g *git) Update(name string) (err error) {
-	_, err = g.ExecuteWithArgs(cmdGitUpdate, name)
-	if err != nil {
+	// the update deploys the generated branch, so it should be run before the worktree was released
+	if err = g.Update(ctx, name); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (g *git) SetSvnTag(name, tag string) (err error) {
-	g.mu.Lock()
-	defer g.mu.Unlock()
-	t, ok := g.RemoteBranches[name]
-	if !ok {
-		return errors.New(fmt.Sprintf(errGitBranchWasNotExisted, name))
+func (g *git) Update(ctx context.Context, name string) (err error) {
+	_, err = g.ExecuteWithArgs(ctx, cmdGitUpdate, name)
+	if err != nil {
+		return err
 	}
-	t.SvnTag = tag
 	return nil
 }
 
-func (g *git) SvnSync(name, svnWorkDir string) (err error) {
-	g.mu.RLock()
-	defer g.mu.RUnlock()
-	t, ok := g.RemoteBranches[name]
+func (g *git) SvnSync(ctx context.Context, name, svnWorkDir string) (err error) {
+	t, ok := g.branch(name)
 	if !ok {
 		return errors.New(fmt.Sprintf(errGitBranchWasNotExisted, name))
 	}
 	if t.SvnTag == "" {
 		return errors.New(fmt.Sprintf(errSvnTagWasNull, name))
 	}
-	err = g.CheckOutBranch(name)
+	ctx, release, err := g.Checkout(ctx, name)
 	if err != nil {
 		return err
 	}
-	_, err = g.ExecuteWithArgs(cmdSvnSync, t.SvnTag, svnWorkDir)
+	defer release()
+	_, err = g.ExecuteWithArgs(ctx, cmdSvnSync, t.SvnTag, svnWorkDir)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (g *git) FtpCompress(name, patchType, version, flags string) (err error) {
-	if err = g.CheckOutBranch(name); err != nil {
-		_ = g.Revert()
-		return err
+// FtpCompress compresses the branch, the zips were left in the returned workDir until the release was called
+func (g *git) FtpCompress(ctx context.Context, name, patchType, version, flags string) (workDir string, release func(), err error) {
+	ctx, release, err = g.Checkout(ctx, name)
+	if err != nil {
+		return workDir, nil, err
 	}
-	_, err = g.ExecuteWithArgs(cmdFtpCompress, patchType, version, flags)
+	_, err = g.ExecuteWithArgs(ctx, cmdFtpCompress, patchType, version, flags)
 	if err != nil {
-		_ = g.Revert()
-		return err
+		release()
+		return workDir, nil, err
 	}
-	return nil
+	return g.WorkDir(ctx), release, nil
 }
 
 func (g *git) ChangeTaskCount(incr int32) {
@@ -327,22 +360,31 @@ func (g *git) ChangeTaskCount(incr int32) {
 
 func (g *git) LoopChan() {
 	defer close(g.TaskChan)
-	tick := time.NewTicker(time.Second * 10)
-	defer tick.Stop()
+	poll := newPoller(g.conf.Poll)
+	defer poll.Stop()
 	for {
 		select {
 		case <-g.ctx.Done():
 			return
 		case c := <-g.TaskChan:
-			g.CurrentTask = c
-			if err := g.HandleCommand(c); err != nil {
+			g.setCurrentTask(c)
+			err := g.HandleCommand(c)
+			if err != nil {
 				klog.V(2).Infof("LoopChan HandleCommand err:%v", err)
 			}
+			if c.done != nil {
+				c.done <- err
+			}
+			// the refresh on demand postpones the next poll as well
+			if c.cmd == cmdGitUpdate {
+				poll.Reset(err)
+			}
 			g.ChangeTaskCount(-1)
-			g.CurrentTask = nil
-		case <-tick.C:
+			g.setCurrentTask(nil)
+		case <-poll.C():
 			if err := g.SendCommand(&GitCmd{cmd: cmdGitUpdate}); err != nil {
 				klog.V(2).Infof("LoopChan SendCommand err:%v", err)
+				poll.Reset(err)
 			}
 		}
 	}
@@ -363,26 +405,57 @@ func (g *git) SendCommand(c *GitCmd) (err error) {
 	}
 }
 
+// Refresh fetches the remote branches at once instead of waiting for the next tick
+func (g *git) Refresh() error {
+	return g.SendCommand(&GitCmd{cmd: cmdGitUpdate})
+}
+
+// RefreshSync is the Refresh which waits for the result of the fetch and the listing
+func (g *git) RefreshSync(ctx context.Context) error {
+	c := &GitCmd{cmd: cmdGitUpdate, done: make(chan error, 1)}
+	if err := g.SendCommand(c); err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-c.done:
+		return err
+	}
+}
+
+func (g *git) setCurrentTask(c *GitCmd) {
+	g.statusMu.Lock()
+	defer g.statusMu.Unlock()
+	g.currentTask = c
+}
+
+func (g *git) setRefreshed(err error) {
+	g.statusMu.Lock()
+	defer g.statusMu.Unlock()
+	g.lastRefresh = time.Now()
+	g.lastError = err
+}
+
 func (g *git) HandleCommand(c *GitCmd) (err error) {
 	switch c.cmd {
 	case cmdGitGenerate:
-		g.mu.RLock()
-		defer g.mu.RUnlock()
-		if err := g.Common(c.branchName); err != nil {
-			return err
-		}
-		if err := g.Update(c.branchName); err != nil {
+		if err := g.Common(g.ctx, c.branchName); err != nil {
 			return err
 		}
 	case cmdGitUpdate:
+		if !g.conf.Worktree {
+			g.workDirMu.Lock()
+			defer g.workDirMu.Unlock()
+		}
 		g.mu.Lock()
 		defer g.mu.Unlock()
-		if err := g.FetchAll(); err != nil {
-			return err
-		}
-		if err := g.ShowAll(false); err != nil {
-			return err
+		err = g.FetchAll()
+		if err == nil {
+			err = g.ShowAll(false)
 		}
+		g.setRefreshed(err)
+		return err
 	}
 	return nil
 }
@@ -393,7 +466,17 @@ func (g *git) GetGitInfo() GitInfo {
 	gi := GitInfo{
 		Name:         g.Name,
 		ListBranches: make([]Branch, 0),
+		TaskCount:    atomic.LoadInt32(&g.TaskCount),
+	}
+	g.statusMu.Lock()
+	if g.currentTask != nil {
+		gi.CurrentTask = g.currentTask.String()
 	}
+	gi.LastRefresh = g.lastRefresh
+	if g.lastError != nil {
+		gi.LastError = g.lastError.Error()
+	}
+	g.statusMu.Unlock()
 	for _, v := range g.ListBranches {
 		if t, ok := g.RemoteBranches[v]; ok {
 			gi.ListBranches = append(gi.ListBranches, *t)
@@ -402,8 +485,8 @@ func (g *git) GetGitInfo() GitInfo {
 	return gi
 }
 
-func NewGitOperator(v *ProjectConfig, ctx context.Context) GitOperator {
-	var git GitOperator = &git{
+func NewGitOperator(v *ProjectConfig, s Store, ctx context.Context) GitOperator {
+	g := &git{
 		conf:           v.Git,
 		ScriptPath:     fmt.Sprintf("%s%s", v.ScriptsPath, gitScriptName),
 		Name:           v.ProjectName,
@@ -411,8 +494,23 @@ func NewGitOperator(v *ProjectConfig, ctx context.Context) GitOperator {
 		ListBranches:   make([]string, 0),
 		TaskCount:      0,
 		TaskChan:       make(chan *GitCmd, 1024),
+		store:          s,
+		filter:         newBranchFilter(v.Git.Branches),
 		ctx:            ctx,
 	}
+	svnTags, err := s.LoadSvnTags()
+	if err != nil {
+		klog.V(2).Info(err)
+		svnTags = make(map[string]string, 0)
+	}
+	g.svnTags = svnTags
+	backend, err := newGitBackend(v.Git, g.ExecuteWithArgs)
+	if err != nil {
+		klog.V(2).Info(err)
+		backend, _ = newGitBackend(GitConfig{Backend: GitBackendScript}, g.ExecuteWithArgs)
+	}
+	g.backend = backend
+	var git GitOperator = g
 	go git.LoopChan()
 	if err := git.SendCommand(&GitCmd{cmd: cmdGitUpdate}); err != nil {
 		klog.V(2).Infof("NewGitOperator SendCommand err:%v", err)
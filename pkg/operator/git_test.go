@@ -0,0 +1,35 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func Test_git_RefreshSync(t *testing.T) {
+	_, workDir := newTestRemote(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	g := NewGitOperator(&ProjectConfig{
+		ProjectName: "projectName",
+		Git:         GitConfig{WorkDir: workDir, Backend: GitBackendNative},
+	}, NewMemoryStore(), ctx).(*git)
+	if err := g.RefreshSync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	gi := g.GetGitInfo()
+	if len(gi.ListBranches) != 2 || gi.LastRefresh.IsZero() || gi.LastError != "" {
+		t.Errorf("GetGitInfo() = %v, want 2 branches refreshed without error", gi)
+	}
+	g.mu.Lock()
+	g.backend = &scriptBackend{exec: func(ctx context.Context, args ...string) (res []byte, err error) {
+		return res, errors.New("unreachable")
+	}}
+	g.mu.Unlock()
+	if err := g.RefreshSync(ctx); err == nil {
+		t.Errorf("RefreshSync() error = nil, want the error of the fetch")
+	}
+	if gi := g.GetGitInfo(); gi.LastError != "unreachable" || len(gi.ListBranches) != 2 {
+		t.Errorf("GetGitInfo() = %v, want the last error and the previous branches", gi)
+	}
+}
@@ -0,0 +1,163 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog"
+)
+
+// the backends of the git operator which could be declared in GitConfig.Backend
+const (
+	GitBackendScript = "script"
+	GitBackendNative = "native"
+)
+
+const (
+	errGitBackendUnknown     = "the git backend `%s` is unknown"
+	errGitBackendUnsupported = "the git backend `%s` does not support %s"
+)
+
+// gitBackend runs the read operations of the git repository in the WorkDir,
+// the project-specific hooks (generate/update/compress) were always run by git.sh
+type gitBackend interface {
+	Fetch(ctx context.Context) error
+	// Branches returns the remote branches and the name of the checked out local branch
+	Branches(ctx context.Context) (res []RemoteBranch, active string, err error)
+	// Checkout resets the local branch to its remote one and checks it out, the local changes would be thrown away
+	Checkout(ctx context.Context, name string) error
+	Revert(ctx context.Context) error
+	// Log returns the latest commits of the remote branch, or any revision
+	Log(ctx context.Context, ref string, limit int) (res []Commit, err error)
+	// Diff returns the changed files between the two revisions
+	Diff(ctx context.Context, from, to string) (res []FileChange, err error)
+}
+
+// RemoteBranch is a branch of the origin, the Head was empty if the backend could not parse it
+type RemoteBranch struct {
+	Name string
+	Head *Commit
+}
+
+// scriptBackend runs git.sh and parses its text output
+type scriptBackend struct {
+	exec func(ctx context.Context, args ...string) (res []byte, err error)
+}
+
+func (sb *scriptBackend) Fetch(ctx context.Context) error {
+	_, err := sb.exec(ctx, cmdGitFetchAll)
+	return err
+}
+
+func (sb *scriptBackend) Branches(ctx context.Context) (res []RemoteBranch, active string, err error) {
+	out, err := sb.exec(ctx, cmdGitShowAll)
+	if err != nil {
+		return res, active, err
+	}
+	res = make([]RemoteBranch, 0)
+	for _, v := range strings.Split(string(out), "\n") {
+		if v == "" {
+			continue
+		}
+		v = strings.Replace(v, " ", "", -1)
+		s := strings.Replace(v, "*", "", -1)
+		activeMatched, err := regexp.Match(`\*`, []byte(v))
+		if err != nil {
+			return res, active, errors.New(fmt.Sprintf("ShowAll regexp.Match active err:%v\n", err))
+		}
+		if activeMatched == true {
+			active = s
+		}
+		if matched, err := regexp.Match(`remotes`, []byte(v)); err != nil {
+			return res, active, errors.New(fmt.Sprintf("ShowAll regexp.Match local/remote err:%v\n", err))
+		} else if matched == true {
+			res = append(res, RemoteBranch{Name: strings.Replace(s, remoteBranchPrefix, "", -1)})
+		}
+	}
+	// the head commits were optional for the listing
+	out, err = sb.exec(ctx, cmdGitHeads)
+	if err != nil {
+		klog.V(2).Info(err)
+		return res, active, nil
+	}
+	heads := make(map[string]*Commit, 0)
+	for _, v := range strings.Split(string(out), "\n") {
+		fields := strings.SplitN(v, commitFieldSeparator, 2)
+		if len(fields) != 2 {
+			continue
+		}
+		if c, ok := parseCommit(fields[1]); ok {
+			heads[fields[0]] = c
+		}
+	}
+	for i := range res {
+		res[i].Head = heads[res[i].Name]
+	}
+	return res, active, nil
+}
+
+const (
+	commitFieldSeparator = "\x1f"
+)
+
+// parseCommit parses a line formatted as `hash author email timestamp subject` separated by commitFieldSeparator
+func parseCommit(line string) (c *Commit, ok bool) {
+	fields := strings.SplitN(line, commitFieldSeparator, 5)
+	if len(fields) != 5 {
+		return c, false
+	}
+	sec, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return c, false
+	}
+	return &Commit{
+		Hash:    fields[0],
+		Author:  fields[1],
+		Email:   strings.Trim(fields[2], "<>"),
+		Subject: fields[4],
+		Time:    time.Unix(sec, 0),
+	}, true
+}
+
+func (sb *scriptBackend) Checkout(ctx context.Context, name string) error {
+	_, err := sb.exec(ctx, cmdGitCheckOut, name, fmt.Sprintf("%s%s", remoteBranchPrefix, name))
+	return err
+}
+
+func (sb *scriptBackend) Revert(ctx context.Context) error {
+	_, err := sb.exec(ctx, cmdGitRevert)
+	return err
+}
+
+func (sb *scriptBackend) Log(ctx context.Context, ref string, limit int) (res []Commit, err error) {
+	out, err := sb.exec(ctx, cmdGitLog, ref, strconv.Itoa(limit))
+	if err != nil {
+		return res, err
+	}
+	res = make([]Commit, 0)
+	for _, v := range strings.Split(string(out), "\n") {
+		if c, ok := parseCommit(v); ok {
+			res = append(res, *c)
+		}
+	}
+	return res, nil
+}
+
+func (sb *scriptBackend) Diff(ctx context.Context, from, to string) (res []FileChange, err error) {
+	return res, errors.New(fmt.Sprintf(errGitBackendUnsupported, GitBackendScript, "diff"))
+}
+
+func newGitBackend(conf GitConfig, exec func(ctx context.Context, args ...string) (res []byte, err error)) (gitBackend, error) {
+	switch conf.Backend {
+	case "", GitBackendScript:
+		return &scriptBackend{exec: exec}, nil
+	case GitBackendNative:
+		return &nativeBackend{workDir: conf.WorkDir}, nil
+	}
+	return nil, errors.New(fmt.Sprintf(errGitBackendUnknown, conf.Backend))
+}
@@ -0,0 +1,38 @@
+package operator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func Test_scriptBackend(t *testing.T) {
+	outputs := map[string]string{
+		cmdGitShowAll: "* master\n  remotes/origin/dev\n  remotes/origin/master\n",
+		cmdGitHeads: strings.Join([]string{
+			"dev\x1fh1\x1fgpt\x1f<gpt@example.com>\x1f1600000000\x1fadd b",
+			"master\x1fh2\x1fgpt\x1f<gpt@example.com>\x1f1500000000\x1fadd a",
+		}, "\n"),
+		cmdGitLog: "h1\x1fgpt\x1fgpt@example.com\x1f1600000000\x1fadd b\nh2\x1fgpt\x1fgpt@example.com\x1f1500000000\x1fadd a\n",
+	}
+	sb := &scriptBackend{exec: func(ctx context.Context, args ...string) (res []byte, err error) {
+		return []byte(outputs[args[0]]), nil
+	}}
+	branches, active, err := sb.Branches(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(branches) != 2 || active != "master" {
+		t.Fatalf("Branches() = %v, %s, want dev and master", branches, active)
+	}
+	if h := branches[0].Head; h == nil || h.Hash != "h1" || h.Email != "gpt@example.com" || h.Time.Unix() != 1600000000 {
+		t.Errorf("Branches() head of dev = %v, want h1", h)
+	}
+	commits, err := sb.Log(context.Background(), "dev", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 || commits[1].Subject != "add a" {
+		t.Errorf("Log() = %v, want 2 commits", commits)
+	}
+}
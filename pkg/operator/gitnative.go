@@ -0,0 +1,240 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+const (
+	remoteOrigin = "origin"
+
+	errGitRevisionWasNotExisted = "the revision `%s` of the git is not existed err:%v"
+)
+
+// nativeBackend operates the repository in the WorkDir with go-git, it never forks the git processes
+type nativeBackend struct {
+	workDir string
+}
+
+func (nb *nativeBackend) open() (*gogit.Repository, error) {
+	return gogit.PlainOpen(nb.workDir)
+}
+
+// Fetch fetches the origin and prunes the remote branches which were deleted, like `git fetch -p`
+func (nb *nativeBackend) Fetch(ctx context.Context) error {
+	r, err := nb.open()
+	if err != nil {
+		return err
+	}
+	err = r.FetchContext(ctx, &gogit.FetchOptions{RemoteName: remoteOrigin, Force: true})
+	if err != nil && err != gogit.NoErrAlreadyUpToDate {
+		return err
+	}
+	remote, err := r.Remote(remoteOrigin)
+	if err != nil {
+		return err
+	}
+	refs, err := remote.ListContext(ctx, &gogit.ListOptions{})
+	if err != nil {
+		return err
+	}
+	existed := make(map[string]struct{}, len(refs))
+	for _, v := range refs {
+		if v.Name().IsBranch() {
+			existed[v.Name().Short()] = struct{}{}
+		}
+	}
+	stale := make([]plumbing.ReferenceName, 0)
+	iter, err := r.References()
+	if err != nil {
+		return err
+	}
+	_ = iter.ForEach(func(ref *plumbing.Reference) error {
+		if name, ok := remoteBranchName(ref); ok {
+			if _, ok := existed[name]; !ok {
+				stale = append(stale, ref.Name())
+			}
+		}
+		return nil
+	})
+	for _, v := range stale {
+		if err := r.Storer.RemoveReference(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remoteBranchName returns the short name of the branch of the origin
+func remoteBranchName(ref *plumbing.Reference) (name string, ok bool) {
+	if !ref.Name().IsRemote() || ref.Type() != plumbing.HashReference {
+		return name, false
+	}
+	name = strings.TrimPrefix(ref.Name().String(), fmt.Sprintf("refs/remotes/%s/", remoteOrigin))
+	if name == ref.Name().String() || name == "HEAD" {
+		return name, false
+	}
+	return name, true
+}
+
+func (nb *nativeBackend) Branches(ctx context.Context) (res []RemoteBranch, active string, err error) {
+	r, err := nb.open()
+	if err != nil {
+		return res, active, err
+	}
+	if head, err := r.Head(); err == nil && head.Name().IsBranch() {
+		active = head.Name().Short()
+	}
+	iter, err := r.References()
+	if err != nil {
+		return res, active, err
+	}
+	res = make([]RemoteBranch, 0)
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		name, ok := remoteBranchName(ref)
+		if !ok {
+			return nil
+		}
+		b := RemoteBranch{Name: name}
+		if c, err := r.CommitObject(ref.Hash()); err == nil {
+			b.Head = newCommit(c)
+		}
+		res = append(res, b)
+		return nil
+	})
+	return res, active, err
+}
+
+func (nb *nativeBackend) Checkout(ctx context.Context, name string) error {
+	r, err := nb.open()
+	if err != nil {
+		return err
+	}
+	if err := nb.revert(r); err != nil {
+		return err
+	}
+	remote, err := r.Reference(plumbing.NewRemoteReferenceName(remoteOrigin, name), true)
+	if err != nil {
+		return err
+	}
+	local := plumbing.NewBranchReferenceName(name)
+	if err := r.Storer.SetReference(plumbing.NewHashReference(local, remote.Hash())); err != nil {
+		return err
+	}
+	// track the remote branch as `git checkout -B --track` did, so that the pull of the hooks would work
+	cfg, err := r.Config()
+	if err != nil {
+		return err
+	}
+	cfg.Branches[name] = &config.Branch{Name: name, Remote: remoteOrigin, Merge: local}
+	if err := r.SetConfig(cfg); err != nil {
+		return err
+	}
+	w, err := r.Worktree()
+	if err != nil {
+		return err
+	}
+	return w.Checkout(&gogit.CheckoutOptions{Branch: local, Force: true})
+}
+
+func (nb *nativeBackend) Revert(ctx context.Context) error {
+	r, err := nb.open()
+	if err != nil {
+		return err
+	}
+	return nb.revert(r)
+}
+
+// revert throws away the local changes including the untracked files, like `git add --all && git reset --hard`
+func (nb *nativeBackend) revert(r *gogit.Repository) error {
+	w, err := r.Worktree()
+	if err != nil {
+		return err
+	}
+	if err := w.Reset(&gogit.ResetOptions{Mode: gogit.HardReset}); err != nil {
+		return err
+	}
+	return w.Clean(&gogit.CleanOptions{Dir: true})
+}
+
+// resolve resolves the name of the remote branch first, and then any other revision
+func (nb *nativeBackend) resolve(r *gogit.Repository, ref string) (*object.Commit, error) {
+	if v, err := r.Reference(plumbing.NewRemoteReferenceName(remoteOrigin, ref), true); err == nil {
+		return r.CommitObject(v.Hash())
+	}
+	h, err := r.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf(errGitRevisionWasNotExisted, ref, err))
+	}
+	return r.CommitObject(*h)
+}
+
+func (nb *nativeBackend) Log(ctx context.Context, ref string, limit int) (res []Commit, err error) {
+	r, err := nb.open()
+	if err != nil {
+		return res, err
+	}
+	c, err := nb.resolve(r, ref)
+	if err != nil {
+		return res, err
+	}
+	iter, err := r.Log(&gogit.LogOptions{From: c.Hash})
+	if err != nil {
+		return res, err
+	}
+	defer iter.Close()
+	res = make([]Commit, 0)
+	err = iter.ForEach(func(c *object.Commit) error {
+		if limit > 0 && len(res) >= limit {
+			return storer.ErrStop
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		res = append(res, *newCommit(c))
+		return nil
+	})
+	return res, err
+}
+
+func (nb *nativeBackend) Diff(ctx context.Context, from, to string) (res []FileChange, err error) {
+	r, err := nb.open()
+	if err != nil {
+		return res, err
+	}
+	fc, err := nb.resolve(r, from)
+	if err != nil {
+		return res, err
+	}
+	tc, err := nb.resolve(r, to)
+	if err != nil {
+		return res, err
+	}
+	patch, err := fc.PatchContext(ctx, tc)
+	if err != nil {
+		return res, err
+	}
+	res = make([]FileChange, 0)
+	for _, v := range patch.Stats() {
+		res = append(res, FileChange{Name: v.Name, Additions: v.Addition, Deletions: v.Deletion})
+	}
+	return res, nil
+}
+
+func newCommit(c *object.Commit) *Commit {
+	return &Commit{
+		Hash:    c.Hash.String(),
+		Author:  c.Author.Name,
+		Email:   c.Author.Email,
+		Subject: strings.SplitN(strings.TrimSpace(c.Message), "\n", 2)[0],
+		Time:    c.Author.When,
+	}
+}
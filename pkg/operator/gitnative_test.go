@@ -0,0 +1,116 @@
+package operator
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// newTestRemote creates a bare origin with the branches master and dev, and a clone of it as the WorkDir
+func newTestRemote(t *testing.T) (origin, workDir string) {
+	root, err := ioutil.TempDir("", "go-gpt-git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(root) })
+	origin = filepath.Join(root, "origin.git")
+	if _, err := gogit.PlainInit(origin, true); err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(root, "src")
+	r, err := gogit.PlainInit(src, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.CreateRemote(&config.RemoteConfig{Name: remoteOrigin, URLs: []string{origin}}); err != nil {
+		t.Fatal(err)
+	}
+	w, err := r.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit := func(file, content, msg string) {
+		if err := ioutil.WriteFile(filepath.Join(src, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Add(file); err != nil {
+			t.Fatal(err)
+		}
+		sig := &object.Signature{Name: "gpt", Email: "gpt@example.com", When: time.Now()}
+		if _, err := w.Commit(msg, &gogit.CommitOptions{Author: sig}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit("a.txt", "a\n", "add a")
+	if err := w.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("dev"), Create: true}); err != nil {
+		t.Fatal(err)
+	}
+	commit("b.txt", "b\nb\n", "add b\n\nthe body")
+	err = r.Push(&gogit.PushOptions{RemoteName: remoteOrigin, RefSpecs: []config.RefSpec{"refs/heads/*:refs/heads/*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	workDir = filepath.Join(root, "work")
+	if _, err := gogit.PlainClone(workDir, false, &gogit.CloneOptions{URL: origin}); err != nil {
+		t.Fatal(err)
+	}
+	return origin, workDir
+}
+
+func Test_nativeBackend(t *testing.T) {
+	_, workDir := newTestRemote(t)
+	nb := &nativeBackend{workDir: workDir}
+	ctx := context.Background()
+	if err := nb.Fetch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	branches, active, err := nb.Branches(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(branches) != 2 || active != "master" {
+		t.Fatalf("Branches() = %v, %s, want master and dev", branches, active)
+	}
+	for _, v := range branches {
+		if v.Head == nil || v.Head.Author != "gpt" {
+			t.Errorf("Branches() head of %s = %v, want the commit of gpt", v.Name, v.Head)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(workDir, "dirty.txt"), []byte("dirty"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := nb.Checkout(ctx, "dev"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(workDir, "b.txt")); err != nil {
+		t.Errorf("Checkout() did not check out the dev: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(workDir, "dirty.txt")); !os.IsNotExist(err) {
+		t.Errorf("Checkout() did not clean the untracked file")
+	}
+	if _, active, _ := nb.Branches(ctx); active != "dev" {
+		t.Errorf("Branches() active = %s, want dev", active)
+	}
+	commits, err := nb.Log(ctx, "dev", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 1 || commits[0].Subject != "add b" {
+		t.Errorf("Log() = %v, want the commit `add b`", commits)
+	}
+	changes, err := nb.Diff(ctx, "master", "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Name != "b.txt" || changes[0].Additions != 2 {
+		t.Errorf("Diff() = %v, want b.txt with 2 additions", changes)
+	}
+}
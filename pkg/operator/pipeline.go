@@ -0,0 +1,93 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	errPipelineWasNotExisted = "the pipeline: %s is not existed"
+	errPipelineStepFailed    = "the pipeline: %s stopped at the step %d/%d %s task:%d err:%v"
+	errPipelineStepCancelled = "the task:%d was cancelled"
+
+	msgPipelineStep     = "the pipeline: %s runs the step %d/%d %s task:%d"
+	msgPipelineContinue = "the step %d/%d %s task:%d failed, continue on error"
+)
+
+// GetPipeline returns the pipeline by the name
+func GetPipeline(pipelines []PipelineConfig, name string) (pc PipelineConfig, err error) {
+	for _, v := range pipelines {
+		if v.Name == name {
+			return v, nil
+		}
+	}
+	return pc, errors.New(fmt.Sprintf(errPipelineWasNotExisted, name))
+}
+
+// stepCommand merges the parameters of the pipeline run and the step
+func stepCommand(c Command, step PipelineStep) *Command {
+	c.Command = step.Command
+	if step.Message != "" {
+		c.Message = step.Message
+	}
+	if step.ZipType != "" {
+		c.ZipType = step.ZipType
+	}
+	if step.ZipFlags != "" {
+		c.ZipFlags = step.ZipFlags
+	}
+	return &c
+}
+
+// runPipeline runs the steps of the pipeline one by one as the children of the parent task,
+// they are run in place because the worker was occupied by the parent task
+func (w *worker) runPipeline(ctx context.Context, parent *Task) error {
+	pc, err := GetPipeline(w.conf.Pipelines, parent.Command.Pipeline)
+	if err != nil {
+		return err
+	}
+	total := len(pc.Steps)
+	for i, step := range pc.Steps {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		child := w.tasks.NewChildTask(ctx, parent, stepCommand(parent.Command, step))
+		parent.AppendMessage(fmt.Sprintf(msgPipelineStep, pc.Name, i+1, total, step.Command, child.Id))
+		if err := w.runChild(ctx, child); err != nil {
+			if step.ContinueOnError {
+				parent.AppendMessage(fmt.Sprintf(msgPipelineContinue, i+1, total, step.Command, child.Id))
+				continue
+			}
+			return errors.New(fmt.Sprintf(errPipelineStepFailed, pc.Name, i+1, total, step.Command, child.Id, err))
+		}
+	}
+	return nil
+}
+
+// runChild runs the task of a pipeline step and retries it in place with the retry policy of its command
+func (w *worker) runChild(ctx context.Context, t *Task) error {
+	defer w.rateLimiter.Forget(t)
+	for {
+		err := w.syncHandler(t)
+		if err == nil {
+			if t.status() == TaskCancelled {
+				return errors.New(fmt.Sprintf(errPipelineStepCancelled, t.Id))
+			}
+			return nil
+		}
+		t.AppendMessage(err.Error())
+		delay, ok := w.nextAttempt(t, err)
+		if !ok {
+			t.ChangeStatus(TaskError)
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			t.ChangeStatus(TaskCancelled)
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package operator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_stepCommand(t *testing.T) {
+	run := Command{
+		ProjectName: "projectName",
+		BranchName:  "release/1.0",
+		Command:     TaskCmdPipeline,
+		Message:     "release 1.0",
+		ZipType:     ZipTypeAll,
+		ZipFlags:    "-a",
+		Pipeline:    "release",
+	}
+	tests := []struct {
+		name string
+		step PipelineStep
+		want *Command
+	}{
+		{
+			name: "shared parameters",
+			step: PipelineStep{Command: TaskCmdSvnCommit},
+			want: &Command{ProjectName: "projectName", BranchName: "release/1.0", Command: TaskCmdSvnCommit, Message: "release 1.0", ZipType: ZipTypeAll, ZipFlags: "-a", Pipeline: "release"},
+		},
+		{
+			name: "overridden parameters",
+			step: PipelineStep{Command: TaskCmdFtpUpload, ZipType: ZipTypePatch, ZipFlags: "-p"},
+			want: &Command{ProjectName: "projectName", BranchName: "release/1.0", Command: TaskCmdFtpUpload, Message: "release 1.0", ZipType: ZipTypePatch, ZipFlags: "-p", Pipeline: "release"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stepCommand(run, tt.step); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("stepCommand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPipeline(t *testing.T) {
+	pipelines := []PipelineConfig{{Name: "release"}, {Name: "patch"}}
+	if pc, err := GetPipeline(pipelines, "patch"); err != nil || pc.Name != "patch" {
+		t.Errorf("GetPipeline() = %v, %v, want patch", pc, err)
+	}
+	if _, err := GetPipeline(pipelines, "nightly"); err == nil {
+		t.Errorf("GetPipeline() error = nil, want the not existed error")
+	}
+}
@@ -0,0 +1,85 @@
+package operator
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	defaultPollInterval   = time.Second * 10
+	defaultPollMaxBackoff = time.Minute * 5
+	defaultPollJitter     = 0.1
+)
+
+// poller schedules the polls of the remote, the interval was doubled for each failure in a row until MaxBackoff,
+// and it was jittered so that the projects would not poll their remotes at the same time
+type poller struct {
+	conf     PollConfig
+	failures int
+	timer    *time.Timer
+}
+
+func newPoller(conf PollConfig) *poller {
+	if conf.Interval <= 0 {
+		conf.Interval = defaultPollInterval
+	}
+	if conf.MaxBackoff < conf.Interval {
+		conf.MaxBackoff = defaultPollMaxBackoff
+		if conf.MaxBackoff < conf.Interval {
+			conf.MaxBackoff = conf.Interval
+		}
+	}
+	if conf.Jitter <= 0 {
+		conf.Jitter = defaultPollJitter
+	}
+	p := &poller{conf: conf}
+	if !conf.Disabled {
+		p.timer = time.NewTimer(p.delay())
+	}
+	return p
+}
+
+func (p *poller) delay() time.Duration {
+	d := p.conf.Interval
+	for i := 0; i < p.failures && d < p.conf.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.conf.MaxBackoff {
+		d = p.conf.MaxBackoff
+	}
+	return wait.Jitter(d, p.conf.Jitter)
+}
+
+// C returns nil if the polling was disabled, so that it would never be selected
+func (p *poller) C() <-chan time.Time {
+	if p.timer == nil {
+		return nil
+	}
+	return p.timer.C
+}
+
+// Reset schedules the next poll by the result of the last one, it should be called by the goroutine receiving C
+func (p *poller) Reset(err error) {
+	if err != nil {
+		p.failures++
+	} else {
+		p.failures = 0
+	}
+	if p.timer == nil {
+		return
+	}
+	if !p.timer.Stop() {
+		select {
+		case <-p.timer.C:
+		default:
+		}
+	}
+	p.timer.Reset(p.delay())
+}
+
+func (p *poller) Stop() {
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+}
@@ -0,0 +1,49 @@
+package operator
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_poller_delay(t *testing.T) {
+	p := newPoller(PollConfig{Interval: time.Second, MaxBackoff: time.Second * 5, Jitter: 0.5})
+	defer p.Stop()
+	tests := []struct {
+		err  error
+		want time.Duration
+	}{
+		{err: errors.New("failed"), want: time.Second * 2},
+		{err: errors.New("failed"), want: time.Second * 4},
+		{err: errors.New("failed"), want: time.Second * 5},
+		{err: errors.New("failed"), want: time.Second * 5},
+		{err: nil, want: time.Second},
+	}
+	for i, tt := range tests {
+		p.Reset(tt.err)
+		if d := p.delay(); d < tt.want || d > tt.want*3/2 {
+			t.Errorf("#%d delay() = %v, want %v with the jitter 0.5", i, d, tt.want)
+		}
+	}
+}
+
+func Test_poller_C(t *testing.T) {
+	p := newPoller(PollConfig{Disabled: true})
+	p.Reset(nil)
+	if p.C() != nil {
+		t.Errorf("C() of the disabled poller should be nil")
+	}
+	p = newPoller(PollConfig{Interval: time.Millisecond})
+	defer p.Stop()
+	select {
+	case <-p.C():
+	case <-time.After(time.Second):
+		t.Fatal("C() did not fire")
+	}
+	p.Reset(nil)
+	select {
+	case <-p.C():
+	case <-time.After(time.Second):
+		t.Fatal("C() did not fire after Reset()")
+	}
+}
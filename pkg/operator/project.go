@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"time"
 
 	"k8s.io/klog"
@@ -14,16 +16,40 @@ type Project interface {
 	GetProject(projectName string) (p *project, err error)
 	GetGitInfo(projectName string) (gi GitInfo, err error)
 	GetAllGitInfo() (res map[string]GitInfo, err error)
-	GitGenerate(projectName, branchName string) error // needed async
+	GitRefresh(projectName string) (gi GitInfo, err error)
+	GitGenerate(ctx context.Context, projectName, branchName string) error // needed async
 	GitSetBranchSvnTag(projectName, branchName, svnTag string) error
-	SvnCommit(projectName, branchName, svnMessage string) error // needed async
+	GitDeleteBranchSvnTag(projectName, branchName string) error
+	GitSvnTags(projectName string) (res []SvnTagMapping, err error)
+	GitLog(projectName, branchName string, limit int) (res []Commit, err error)
+	GitWebhook(projectName string, header http.Header, body []byte) (ids []int, err error)
+	SvnCommit(ctx context.Context, projectName, branchName, svnMessage string) error  // needed async
+	SvnPrepare(ctx context.Context, projectName, branchName, svnMessage string) error // needed async
+	SvnPrepared(projectName string) (res *SvnChangeSet, err error)
+	SvnConfirm(ctx context.Context, projectName string, id int) error
+	SvnDiscard(projectName string, id int) error
+	SvnRollback(ctx context.Context, projectName, revision, svnMessage string) error // needed async
 	SvnLog(projectName string, showNumber int) (res []Logentry, err error)
+	SvnLogList(projectName string, filter SvnLogFilter) (res SvnLogPage, err error)
+	SvnInfo(projectName string) (res SvnInfo, err error)
+	SvnCopy(ctx context.Context, projectName, kind, branchName, name, svnMessage string) error // needed async
+	SvnCopies(projectName, kind string) (res []SvnCopyEntry, err error)
 	FtpLog(projectName, filter string) (res []Entry, err error)
 	FtpReadFile(projectName, fileName string) (res []byte, err error)
 	FtpWriteFile(projectName, fileName, content string) error
-	FtpCompress(projectName, branchName, zipType, zipFlags string) error // needed async
-	AsyncTask(c *Command) error
+	FtpCompress(ctx context.Context, projectName, branchName, zipType, zipFlags string) error // needed async
+	ReleaseChangelog(projectName, branchName string) (res Changelog, err error)
+	AsyncTask(c *Command) (id int, err error)
 	TaskAll(projectName string) (res map[int]Task, err error)
+	TaskList(projectName string, filter TaskFilter) (res TaskPage, err error)
+	TaskGet(projectName string, id int) (res *Task, err error)
+	TaskCancel(projectName string, id int) error
+	PipelineList(projectName string) (res []PipelineConfig, err error)
+	ScheduleList(projectName string) (res []Schedule, err error)
+	SchedulePause(projectName, name string) error
+	ScheduleResume(projectName, name string) error
+	ScheduleTrigger(projectName, name string) (id int, err error)
+	TaskSubscribe(projectName string, id int) (history []string, ch <-chan string, unsubscribe func(), err error)
 	OssEnvs(projectName string) (res map[string]string, err error)
 	OssContent(projectName, env string) (nc NoticeContent, err error)
 	OssUpdateContent(projectName, env string, nc NoticeContent) error
@@ -33,6 +59,21 @@ const (
 	errNotExistedProject = "the project: %s is not existed"
 )
 
+// branchCommands are the task commands working on the branch of the git
+var branchCommands = map[string]bool{
+	TaskCmdGitGen:     true,
+	TaskCmdSvnCommit:  true,
+	TaskCmdSvnPrepare: true,
+	TaskCmdFtpUpload:  true,
+}
+
+const (
+	gitRefreshTimeout = time.Minute * 5
+
+	defaultGitLogLimit = 20
+	maxGitLogLimit     = 200
+)
+
 const (
 	ZipTypeAll   = "ser"
 	ZipTypePatch = "pat"
@@ -47,13 +88,16 @@ const (
 )
 
 type project struct {
+	conf ProjectConfig
+
 	git GitOperator
 	svn SvnOperator
 	ftp FtpOperator
 	oss AliYunOss
 
-	worker Worker
-	tasks  *TaskHub
+	worker    Worker
+	tasks     *TaskHub
+	scheduler Scheduler
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -83,6 +127,18 @@ func (ph *projects) GetGitInfo(projectName string) (gi GitInfo, err error) {
 	return p.git.GetGitInfo(), nil
 }
 
+// GitRefresh fetches and lists the branches at once, the GitInfo was returned with the error of the refresh
+func (ph *projects) GitRefresh(projectName string) (gi GitInfo, err error) {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return gi, err
+	}
+	ctx, cancel := context.WithTimeout(p.ctx, gitRefreshTimeout)
+	defer cancel()
+	err = p.git.RefreshSync(ctx)
+	return p.git.GetGitInfo(), err
+}
+
 func (ph *projects) GetAllGitInfo() (res map[string]GitInfo, err error) {
 	res = make(map[string]GitInfo, 0)
 	for k, v := range ph.projects {
@@ -96,17 +152,15 @@ func (ph *projects) GetAllGitInfo() (res map[string]GitInfo, err error) {
 	return res, nil
 }
 
-func (ph *projects) GitGenerate(projectName, branchName string) error {
+func (ph *projects) GitGenerate(ctx context.Context, projectName, branchName string) error {
 	p, err := ph.GetProject(projectName)
 	if err != nil {
 		return err
 	}
-	p.git.RLock()
-	defer p.git.RUnlock()
-	if err := p.git.Common(branchName); err != nil {
+	if err := p.git.CheckBranch(branchName, true); err != nil {
 		return err
 	}
-	if err := p.git.Update(branchName); err != nil {
+	if err := p.git.Common(ctx, branchName); err != nil {
 		return err
 	}
 	return nil
@@ -120,17 +174,57 @@ func (ph *projects) GitSetBranchSvnTag(projectName, branchName, svnTag string) e
 	return p.git.SetSvnTag(branchName, svnTag)
 }
 
-func (ph *projects) SvnCommit(projectName, branchName, svnMessage string) error {
+func (ph *projects) GitDeleteBranchSvnTag(projectName, branchName string) error {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return err
+	}
+	return p.git.DeleteSvnTag(branchName)
+}
+
+func (ph *projects) GitSvnTags(projectName string) (res []SvnTagMapping, err error) {
 	p, err := ph.GetProject(projectName)
 	if err != nil {
+		return res, err
+	}
+	return p.git.SvnTags(), nil
+}
+
+func (ph *projects) GitLog(projectName, branchName string, limit int) (res []Commit, err error) {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return res, err
+	}
+	if limit < 1 {
+		limit = defaultGitLogLimit
+	}
+	if limit > maxGitLogLimit {
+		limit = maxGitLogLimit
+	}
+	// the branch name was passed to `git log`, it should not be taken as an option
+	if err := p.git.CheckBranch(branchName, false); err != nil {
+		return res, err
+	}
+	return p.git.Log(p.ctx, branchName, limit)
+}
+
+func (ph *projects) SvnCommit(ctx context.Context, projectName, branchName, svnMessage string) error {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return err
+	}
+	if err := p.git.CheckBranch(branchName, false); err != nil {
 		return err
 	}
 	p.svn.Lock()
 	defer p.svn.Unlock()
-	if err := p.git.SvnSync(branchName, p.svn.GetFullWorkDir()); err != nil {
+	if cs := p.svn.Prepared(); cs != nil {
+		return errors.New(fmt.Sprintf(errSvnPrepared, cs.Id, cs.Branch))
+	}
+	if err := p.git.SvnSync(ctx, branchName, p.svn.GetFullWorkDir()); err != nil {
 		return err
 	}
-	return p.svn.Commit(svnMessage)
+	return p.svn.Commit(ctx, svnMessage)
 }
 
 func (ph *projects) SvnLog(projectName string, showNumber int) (res []Logentry, err error) {
@@ -165,28 +259,37 @@ func (ph *projects) FtpWriteFile(projectName, fileName, content string) error {
 	return p.ftp.WriteFileContent(fileName, []byte(content))
 }
 
-func (ph *projects) FtpCompress(projectName, branchName, zipType, zipFlags string) error {
+func (ph *projects) FtpCompress(ctx context.Context, projectName, branchName, zipType, zipFlags string) error {
 	p, err := ph.GetProject(projectName)
 	if err != nil {
 		return err
 	}
-	p.git.RLock()
-	defer p.git.RUnlock()
+	if err := p.git.CheckBranch(branchName, false); err != nil {
+		return err
+	}
 	version, err := p.ftp.GetNextVersion()
 	if err != nil {
 		return err
 	}
-	if err := p.git.FtpCompress(branchName, zipType, version, zipFlags); err != nil {
+	workDir, release, err := p.git.FtpCompress(ctx, branchName, zipType, version, zipFlags)
+	if err != nil {
 		return err
 	}
-	defer func() {
-		if err := p.git.Revert(); err != nil {
-			klog.V(2).Info(err)
-		}
-	}()
+	defer release()
 	v := fmt.Sprintf(versionTemplate, time.Now().Format("20060102"), version)
 	introName := fmt.Sprintf(introduceTemplate, v)
-	if err := p.ftp.UploadFile(fmt.Sprintf("%s/%s", p.git.Conf().WorkDir, introName), introName); err != nil {
+	cl, clErr := p.changelog(ctx, branchName, v)
+	if clErr != nil {
+		klog.V(2).Info(clErr)
+	} else if p.conf.Changelog.Introduce {
+		if err := ioutil.WriteFile(fmt.Sprintf("%s/%s", workDir, introName), []byte(cl.Content), 0644); err != nil {
+			return err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := p.ftp.UploadFile(ctx, fmt.Sprintf("%s/%s", workDir, introName), introName); err != nil {
 		return err
 	}
 	switch zipType {
@@ -196,24 +299,120 @@ func (ph *projects) FtpCompress(projectName, branchName, zipType, zipFlags strin
 	}
 	zipName := fmt.Sprintf(serverTemplate, v)
 	klog.Info(zipName)
-	if err := p.ftp.UploadFile(fmt.Sprintf("%s/%s", p.git.Conf().WorkDir, zipName), zipName); err != nil {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := p.ftp.UploadFile(ctx, fmt.Sprintf("%s/%s", workDir, zipName), zipName); err != nil {
 		return err
 	}
 	zipMd5Name := fmt.Sprintf(serverMd5Template, v)
 	klog.Info(zipMd5Name)
-	if err := p.ftp.UploadFile(fmt.Sprintf("%s/%s", p.git.Conf().WorkDir, zipMd5Name), zipMd5Name); err != nil {
+	if err := ctx.Err(); err != nil {
 		return err
 	}
+	if err := p.ftp.UploadFile(ctx, fmt.Sprintf("%s/%s", workDir, zipMd5Name), zipMd5Name); err != nil {
+		return err
+	}
+	if clErr == nil {
+		// the next changelog of the branch starts from this release
+		if err := p.tasks.store.SaveRelease(cl.release()); err != nil {
+			klog.V(2).Info(err)
+		}
+	}
 	return nil
 }
 
-func (ph *projects) AsyncTask(c *Command) error {
+func (ph *projects) ReleaseChangelog(projectName, branchName string) (res Changelog, err error) {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return res, err
+	}
+	if err := p.git.CheckBranch(branchName, false); err != nil {
+		return res, err
+	}
+	// the version is the preview of the next one, it might be taken by the other publish
+	version, err := p.ftp.GetNextVersion()
+	if err != nil {
+		klog.V(2).Info(err)
+	} else {
+		version = fmt.Sprintf(versionTemplate, time.Now().Format("20060102"), version)
+	}
+	return p.changelog(p.ctx, branchName, version)
+}
+
+func (ph *projects) AsyncTask(c *Command) (id int, err error) {
 	p, err := ph.GetProject(c.ProjectName)
 	if err != nil {
-		return err
+		return id, err
 	}
-	p.worker.Add(p.tasks.NewTask(c))
-	return nil
+	if c.Command == TaskCmdPipeline {
+		if _, err := GetPipeline(p.conf.Pipelines, c.Pipeline); err != nil {
+			return id, err
+		}
+	}
+	if c.Command == TaskCmdSvnConfirm {
+		if cs := p.svn.Prepared(); cs == nil || cs.Id != c.PrepareId {
+			return id, errors.New(fmt.Sprintf(errSvnNotPrepared, c.PrepareId))
+		}
+	}
+	if c.Command == TaskCmdSvnRollback {
+		if _, _, err := parseRevisionRange(c.Revision); err != nil {
+			return id, err
+		}
+	}
+	if (c.Command == TaskCmdSvnTag || c.Command == TaskCmdSvnBranch) && c.CopyName != "" && !validSvnCopyName(c.CopyName) {
+		return id, errors.New(fmt.Sprintf(errSvnCopyName, c.CopyName, c.Command))
+	}
+	if err := p.checkCommandBranch(c); err != nil {
+		return id, err
+	}
+	var t *Task
+	switch mode := p.conf.Commands[c.Command].Dedup; mode {
+	case DedupNone:
+		t = p.tasks.NewTask(c)
+	default:
+		var created bool
+		// the running task was taken as the duplicate of the rejected one, e.g. the double click of the generate,
+		// but the coalesced one should not be merged into it which might have passed the point of the change
+		if t, created = p.tasks.NewUniqueTask(c, mode != DedupCoalesce); !created {
+			if mode == DedupCoalesce {
+				t.AppendMessage(fmt.Sprintf(msgTaskCoalesced, time.Now().Format("2006-01-02 15:04:05")))
+				return t.Id, nil
+			}
+			return t.Id, &taskDuplicatedError{id: t.Id}
+		}
+	}
+	p.worker.Add(t)
+	return t.Id, nil
+}
+
+// checkCommandBranch refuses the filtered-out or the missing branch before the task was queued,
+// the protected one was refused as well if it was going to be pushed by the command or the steps of the pipeline
+func (p *project) checkCommandBranch(c *Command) error {
+	commands := []string{c.Command}
+	if c.Command == TaskCmdPipeline {
+		pc, err := GetPipeline(p.conf.Pipelines, c.Pipeline)
+		if err != nil {
+			return err
+		}
+		commands = commands[:0]
+		for _, v := range pc.Steps {
+			commands = append(commands, v.Command)
+		}
+	}
+	checked, push := false, false
+	for _, v := range commands {
+		if branchCommands[v] {
+			checked = true
+		}
+		if v == TaskCmdGitGen {
+			push = true
+		}
+	}
+	if !checked {
+		return nil
+	}
+	return p.git.CheckBranch(c.BranchName, push)
 }
 
 func (ph *projects) TaskAll(projectName string) (res map[int]Task, err error) {
@@ -224,6 +423,93 @@ func (ph *projects) TaskAll(projectName string) (res map[int]Task, err error) {
 	return p.tasks.GetAll(), nil
 }
 
+func (ph *projects) TaskList(projectName string, filter TaskFilter) (res TaskPage, err error) {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return res, err
+	}
+	return p.tasks.List(filter), nil
+}
+
+func (ph *projects) TaskGet(projectName string, id int) (res *Task, err error) {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return res, err
+	}
+	t, err := p.tasks.Get(id)
+	if err != nil {
+		return res, err
+	}
+	s := t.snapshot()
+	return &s, nil
+}
+
+func (ph *projects) TaskCancel(projectName string, id int) error {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return err
+	}
+	t, err := p.tasks.Get(id)
+	if err != nil {
+		return err
+	}
+	return t.Cancel()
+}
+
+func (ph *projects) TaskSubscribe(projectName string, id int) (history []string, ch <-chan string, unsubscribe func(), err error) {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return history, ch, unsubscribe, err
+	}
+	t, err := p.tasks.Get(id)
+	if err != nil {
+		return history, ch, unsubscribe, err
+	}
+	history, ch, unsubscribe = t.Subscribe()
+	return history, ch, unsubscribe, nil
+}
+
+func (ph *projects) PipelineList(projectName string) (res []PipelineConfig, err error) {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return res, err
+	}
+	res = make([]PipelineConfig, 0, len(p.conf.Pipelines))
+	return append(res, p.conf.Pipelines...), nil
+}
+
+func (ph *projects) ScheduleList(projectName string) (res []Schedule, err error) {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return res, err
+	}
+	return p.scheduler.List(), nil
+}
+
+func (ph *projects) SchedulePause(projectName, name string) error {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return err
+	}
+	return p.scheduler.Pause(name)
+}
+
+func (ph *projects) ScheduleResume(projectName, name string) error {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return err
+	}
+	return p.scheduler.Resume(name)
+}
+
+func (ph *projects) ScheduleTrigger(projectName, name string) (id int, err error) {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return id, err
+	}
+	return p.scheduler.Trigger(name)
+}
+
 func (ph *projects) OssEnvs(projectName string) (res map[string]string, err error) {
 	p, err := ph.GetProject(projectName)
 	if err != nil {
@@ -253,15 +539,30 @@ func NewProject(conf []ProjectConfig, ctx context.Context) Project {
 		projects: make(map[string]*project, 0),
 	}
 	for _, v := range conf {
+		store, err := NewStore(v.Store)
+		if err != nil {
+			klog.Errorf("the store of the project: %s fell back to the memory, nothing would be persisted err:%v", v.ProjectName, err)
+			store = NewMemoryStore()
+		}
+		go func(s Store) {
+			<-ctx.Done()
+			if err := s.Close(); err != nil {
+				klog.V(2).Info(err)
+			}
+		}(store)
+		tasks := NewTaskHub(store, ctx)
+		tasks.RunRetention(v.Retention)
 		p := &project{
-			git:    NewGitOperator(&v, ctx),
+			conf:   v,
+			git:    NewGitOperator(&v, store, ctx),
 			svn:    NewSvnOperator(&v, ctx),
 			ftp:    NewFtpOperator(v.Ftp),
 			oss:    NewAliYunOss(v.Oss, ctx),
-			worker: NewWorker(ctx.Done(), ph),
-			tasks:  NewTaskHub(),
+			worker: NewWorker(ctx.Done(), ph, v, tasks),
+			tasks:  tasks,
 			ctx:    ctx,
 		}
+		p.scheduler = NewScheduler(v, tasks, ph.AsyncTask, ctx)
 		ph.Add(v.ProjectName, p)
 	}
 	return ph
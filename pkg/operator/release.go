@@ -0,0 +1,131 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"text/template"
+	"time"
+
+	"k8s.io/klog"
+)
+
+const (
+	defaultChangelogLimit = 100
+
+	defaultChangelogTemplate = `{{.Version}} {{.Branch}}
+{{if .From}}since {{.From.Version}} ({{.From.Time.Format "2006-01-02 15:04:05"}})
+{{end}}{{range .Commits}}- {{.Subject}} ({{.Author}})
+{{end}}{{range .Revisions}}- r{{.Revision}} {{.Msg}} ({{.Author}})
+{{end}}`
+)
+
+// Release is a version which was published to the ftp from a branch
+type Release struct {
+	Branch      string    `json:"branch"`
+	Version     string    `json:"version"`
+	Commit      string    `json:"commit"`
+	SvnRevision string    `json:"svn_revision"`
+	Time        time.Time `json:"time"`
+}
+
+// Changelog contains the git commits and the svn revisions since the last release of the branch
+// swagger:response Changelog
+type Changelog struct {
+	Branch    string     `json:"branch"`
+	Version   string     `json:"version"`
+	From      *Release   `json:"from,omitempty"`
+	Commits   []Commit   `json:"commits"`
+	Revisions []Logentry `json:"revisions"`
+	Content   string     `json:"content"`
+}
+
+// release returns the release which would be recorded after the changelog was published
+func (cl *Changelog) release() Release {
+	r := Release{
+		Branch:  cl.Branch,
+		Version: cl.Version,
+		Time:    time.Now(),
+	}
+	if cl.From != nil {
+		r.Commit = cl.From.Commit
+		r.SvnRevision = cl.From.SvnRevision
+	}
+	if len(cl.Commits) > 0 {
+		r.Commit = cl.Commits[0].Hash
+	}
+	if len(cl.Revisions) > 0 {
+		r.SvnRevision = cl.Revisions[0].Revision
+	}
+	return r
+}
+
+// changelog collects the commits of the branch and the svn revisions after the last release, and renders them with the template
+func (p *project) changelog(ctx context.Context, branchName, version string) (cl Changelog, err error) {
+	conf := p.conf.Changelog
+	limit := conf.Limit
+	if limit < 1 {
+		limit = defaultChangelogLimit
+	}
+	cl = Changelog{
+		Branch:    branchName,
+		Version:   version,
+		Commits:   make([]Commit, 0),
+		Revisions: make([]Logentry, 0),
+	}
+	if r, ok, err := p.tasks.store.LastRelease(branchName); err != nil {
+		return cl, err
+	} else if ok {
+		cl.From = &r
+	}
+	commits, err := p.git.Log(ctx, branchName, limit)
+	if err != nil {
+		return cl, err
+	}
+	for _, v := range commits {
+		if cl.From != nil && v.Hash == cl.From.Commit {
+			break
+		}
+		cl.Commits = append(cl.Commits, v)
+	}
+	// the svn revisions were optional, the svn of the project might not be configured
+	if revisions, err := p.svn.Log(limit); err != nil {
+		klog.V(2).Info(err)
+	} else {
+		for _, v := range revisions {
+			if cl.From != nil && !revisionAfter(v.Revision, cl.From.SvnRevision) {
+				break
+			}
+			cl.Revisions = append(cl.Revisions, v)
+		}
+	}
+	cl.Content, err = renderChangelog(conf.Template, cl)
+	return cl, err
+}
+
+func renderChangelog(text string, cl Changelog) (res string, err error) {
+	if text == "" {
+		text = defaultChangelogTemplate
+	}
+	tpl, err := template.New("changelog").Parse(text)
+	if err != nil {
+		return res, err
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, cl); err != nil {
+		return res, err
+	}
+	return buf.String(), nil
+}
+
+func revisionAfter(revision, since string) bool {
+	r, err := strconv.Atoi(revision)
+	if err != nil {
+		return false
+	}
+	s, err := strconv.Atoi(since)
+	if err != nil {
+		return true
+	}
+	return r > s
+}
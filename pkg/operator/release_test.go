@@ -0,0 +1,54 @@
+package operator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChangelog(t *testing.T) {
+	cl := Changelog{
+		Branch:  "dev",
+		Version: "2020010101",
+		From:    &Release{Branch: "dev", Version: "2019123101", Commit: "h0", SvnRevision: "10", Time: time.Date(2019, 12, 31, 0, 0, 0, 0, time.Local)},
+		Commits: []Commit{
+			{Hash: "h2", Author: "gpt", Subject: "add b"},
+			{Hash: "h1", Author: "gpt", Subject: "add a"},
+		},
+		Revisions: []Logentry{{Revision: "12", Author: "admin", Msg: "sync"}},
+	}
+	got, err := renderChangelog("", cl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "2020010101 dev\nsince 2019123101 (2019-12-31 00:00:00)\n- add b (gpt)\n- add a (gpt)\n- r12 sync (admin)\n"
+	if got != want {
+		t.Errorf("renderChangelog() = %q, want %q", got, want)
+	}
+	r := cl.release()
+	if r.Commit != "h2" || r.SvnRevision != "12" || r.Version != "2020010101" {
+		t.Errorf("release() = %v, want the head h2 and the revision 12", r)
+	}
+	cl.Commits, cl.Revisions = nil, nil
+	if r := cl.release(); r.Commit != "h0" || r.SvnRevision != "10" {
+		t.Errorf("release() = %v, want the previous head h0 and the revision 10", r)
+	}
+}
+
+func Test_revisionAfter(t *testing.T) {
+	if !revisionAfter("12", "10") || revisionAfter("10", "10") || !revisionAfter("1", "") {
+		t.Errorf("revisionAfter() was wrong")
+	}
+}
+
+func TestMemoryStore_Release(t *testing.T) {
+	s := NewMemoryStore()
+	if _, ok, _ := s.LastRelease("dev"); ok {
+		t.Fatalf("LastRelease() ok = true, want false")
+	}
+	if err := s.SaveRelease(Release{Branch: "dev", Version: "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if r, ok, _ := s.LastRelease("dev"); !ok || r.Version != "1" {
+		t.Errorf("LastRelease() = %v, %v, want the version 1", r, ok)
+	}
+}
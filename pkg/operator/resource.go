@@ -0,0 +1,109 @@
+package operator
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// the resources of a project, the tasks locking the same resource would never be run in parallel
+const (
+	ResourceGit = "git"
+	ResourceSvn = "svn"
+	ResourceFtp = "ftp"
+)
+
+const (
+	// resourceConflictDelay is the delay of requeuing the task whose resources were locked by the others
+	resourceConflictDelay = time.Millisecond * 500
+
+	resourceBranchTemplate = "%s:%s"
+)
+
+var defaultCommandResources = map[string][]string{
+	TaskCmdGitGen:      {ResourceGit},
+	TaskCmdSvnCommit:   {ResourceGit, ResourceSvn},
+	TaskCmdSvnPrepare:  {ResourceGit, ResourceSvn},
+	TaskCmdSvnConfirm:  {ResourceSvn},
+	TaskCmdSvnRollback: {ResourceSvn},
+	TaskCmdSvnTag:      {ResourceSvn},
+	TaskCmdSvnBranch:   {ResourceSvn},
+	TaskCmdFtpUpload:   {ResourceGit, ResourceFtp},
+}
+
+// commandResources returns the resources of the command, a pipeline would lock all the resources of its steps.
+// The git resource would be locked per branch if the tasks were run in their own worktrees
+func commandResources(conf ProjectConfig, c Command) []string {
+	res := declaredResources(conf, c)
+	if !conf.Git.Worktree {
+		return res
+	}
+	branched := make([]string, 0, len(res))
+	for _, v := range res {
+		if v == ResourceGit {
+			v = fmt.Sprintf(resourceBranchTemplate, v, c.BranchName)
+		}
+		branched = append(branched, v)
+	}
+	return branched
+}
+
+func declaredResources(conf ProjectConfig, c Command) []string {
+	if v := conf.Commands[c.Command].Resources; len(v) > 0 {
+		return v
+	}
+	if c.Command != TaskCmdPipeline {
+		return defaultCommandResources[c.Command]
+	}
+	pc, err := GetPipeline(conf.Pipelines, c.Pipeline)
+	if err != nil {
+		return nil
+	}
+	set := make(map[string]struct{}, 0)
+	for _, step := range pc.Steps {
+		for _, v := range declaredResources(conf, Command{Command: step.Command}) {
+			set[v] = struct{}{}
+		}
+	}
+	res := make([]string, 0, len(set))
+	for k := range set {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// resourceLocker locks all the resources of a task at once, or none of them
+type resourceLocker struct {
+	mu   sync.Mutex
+	held map[string]struct{}
+}
+
+func newResourceLocker() *resourceLocker {
+	return &resourceLocker{
+		held: make(map[string]struct{}, 0),
+	}
+}
+
+func (rl *resourceLocker) TryLock(resources []string) bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	for _, v := range resources {
+		if _, ok := rl.held[v]; ok {
+			return false
+		}
+	}
+	for _, v := range resources {
+		rl.held[v] = struct{}{}
+	}
+	return true
+}
+
+func (rl *resourceLocker) Unlock(resources []string) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	for _, v := range resources {
+		delete(rl.held, v)
+	}
+}
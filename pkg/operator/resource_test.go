@@ -0,0 +1,58 @@
+package operator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_commandResources(t *testing.T) {
+	conf := ProjectConfig{
+		Commands: map[string]CommandConfig{
+			TaskCmdGitGen: {Resources: []string{ResourceSvn}},
+		},
+		Pipelines: []PipelineConfig{
+			{Name: "release", Steps: []PipelineStep{{Command: TaskCmdSvnCommit}, {Command: TaskCmdFtpUpload}}},
+		},
+	}
+	tests := []struct {
+		name string
+		c    Command
+		want []string
+	}{
+		{name: "configured", c: Command{Command: TaskCmdGitGen}, want: []string{ResourceSvn}},
+		{name: "default", c: Command{Command: TaskCmdFtpUpload}, want: []string{ResourceGit, ResourceFtp}},
+		{name: "pipeline", c: Command{Command: TaskCmdPipeline, Pipeline: "release"}, want: []string{ResourceFtp, ResourceGit, ResourceSvn}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commandResources(conf, tt.c); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("commandResources() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_resourceLocker(t *testing.T) {
+	rl := newResourceLocker()
+	if !rl.TryLock([]string{ResourceGit, ResourceFtp}) {
+		t.Fatalf("TryLock() = false, want true")
+	}
+	if rl.TryLock([]string{ResourceSvn, ResourceGit}) {
+		t.Errorf("TryLock() = true for the conflicting resources")
+	}
+	if !rl.TryLock([]string{ResourceSvn}) {
+		t.Errorf("TryLock() = false for the free resources")
+	}
+	rl.Unlock([]string{ResourceGit, ResourceFtp})
+	if !rl.TryLock([]string{ResourceGit}) {
+		t.Errorf("TryLock() = false after Unlock()")
+	}
+}
+
+func Test_commandResources_worktree(t *testing.T) {
+	conf := ProjectConfig{Git: GitConfig{Worktree: true}}
+	got := commandResources(conf, Command{Command: TaskCmdFtpUpload, BranchName: "dev"})
+	if want := []string{"git:dev", ResourceFtp}; !reflect.DeepEqual(got, want) {
+		t.Errorf("commandResources() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,85 @@
+package operator
+
+import (
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+)
+
+const (
+	defaultRetentionInterval = time.Hour
+
+	msgTaskPruned = "pruned %d tasks"
+)
+
+// expired returns true if the finished task was older than the retention policy allowed
+func (rc RetentionConfig) expired(t *Task, now time.Time) bool {
+	age := now.Sub(t.CreatedAt)
+	if (t.Status == TaskError || t.Status == TaskInterrupted) && rc.FailedMaxAge > 0 && age < rc.FailedMaxAge {
+		return false
+	}
+	if rc.MaxAge > 0 && age < rc.MaxAge {
+		return false
+	}
+	return true
+}
+
+// Prune deletes the finished tasks which were beyond the retention policy from the hub and the store,
+// the children of a pipeline would be pruned together with their parent and the unfinished tasks were always kept
+func (th *TaskHub) Prune(rc RetentionConfig, now time.Time) (n int) {
+	if rc.KeepLast <= 0 && rc.MaxAge <= 0 {
+		return 0
+	}
+	th.mu.Lock()
+	defer th.mu.Unlock()
+	parents := make([]*Task, 0, len(th.Tasks))
+	for _, t := range th.Tasks {
+		if t.ParentId == 0 {
+			parents = append(parents, t)
+		}
+	}
+	sort.Slice(parents, func(i, j int) bool {
+		return parents[i].Id > parents[j].Id
+	})
+	for i, t := range parents {
+		if rc.KeepLast > 0 && i < rc.KeepLast {
+			continue
+		}
+		t.mu.RLock()
+		ok := isTaskFinished(t.Status) && rc.expired(t, now)
+		children := append(make([]int, 0, len(t.Children)), t.Children...)
+		t.mu.RUnlock()
+		if !ok {
+			continue
+		}
+		for _, id := range append(children, t.Id) {
+			if err := th.store.DeleteTask(id); err != nil {
+				klog.V(2).Info(err)
+				continue
+			}
+			if _, ok := th.Tasks[id]; ok {
+				delete(th.Tasks, id)
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// RunRetention prunes the tasks periodically until the ctx of the hub was done
+func (th *TaskHub) RunRetention(rc RetentionConfig) {
+	if rc.KeepLast <= 0 && rc.MaxAge <= 0 {
+		return
+	}
+	interval := rc.Interval
+	if interval <= 0 {
+		interval = defaultRetentionInterval
+	}
+	go wait.Until(func() {
+		if n := th.Prune(rc, time.Now()); n > 0 {
+			klog.Infof(msgTaskPruned, n)
+		}
+	}, interval, th.ctx.Done())
+}
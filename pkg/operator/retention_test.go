@@ -0,0 +1,41 @@
+package operator
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTaskHub_Prune(t *testing.T) {
+	now := time.Now()
+	th := NewTaskHub(NewMemoryStore(), context.Background())
+	newTask := func(status int, age time.Duration) *Task {
+		task := th.NewTask(&Command{Command: TaskCmdGitGen})
+		task.CreatedAt = now.Add(-age)
+		task.ChangeStatus(status)
+		return task
+	}
+	oldFailed := newTask(TaskError, time.Hour*48)
+	oldCompleted := newTask(TaskCompleted, time.Hour*48)
+	stuck := newTask(TaskWaiting, time.Hour*48)
+	recentFailed := newTask(TaskError, time.Hour*12)
+	parent := newTask(TaskCompleted, time.Hour*48)
+	child := th.NewChildTask(context.Background(), parent, &Command{Command: TaskCmdGitGen})
+	child.ChangeStatus(TaskCompleted)
+	latest := newTask(TaskCompleted, time.Hour*48)
+
+	rc := RetentionConfig{KeepLast: 1, MaxAge: time.Hour, FailedMaxAge: time.Hour * 24}
+	if n := th.Prune(rc, now); n != 4 {
+		t.Errorf("Prune() = %d, want 4", n)
+	}
+	for _, v := range []*Task{oldFailed, oldCompleted, parent, child} {
+		if _, err := th.Get(v.Id); err == nil {
+			t.Errorf("the task:%d was not pruned", v.Id)
+		}
+	}
+	for _, v := range []*Task{stuck, recentFailed, latest} {
+		if _, err := th.Get(v.Id); err != nil {
+			t.Errorf("the task:%d was pruned", v.Id)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package operator
+
+import (
+	"net"
+	"os/exec"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// the kinds of the task errors which could be declared in RetryConfig.RetryOn
+const (
+	ErrorKindNetwork = "network"
+	ErrorKindExit    = "exit"
+	ErrorKindTimeout = "timeout"
+	ErrorKindUnknown = "unknown"
+)
+
+const (
+	defaultRetryMaxDelay = time.Second * 1000
+)
+
+// taskError is an error with the kind which was used for classifying whether the task could be retried
+type taskError struct {
+	kind string
+	msg  string
+}
+
+func (e *taskError) Error() string {
+	return e.msg
+}
+
+func newTaskError(kind, msg string) error {
+	return &taskError{
+		kind: kind,
+		msg:  msg,
+	}
+}
+
+// newExecError classifies the err returned by execute, the non-zero exit status of the scripts would be ErrorKindExit
+func newExecError(err error, msg string) error {
+	if _, ok := err.(*exec.ExitError); ok {
+		return newTaskError(ErrorKindExit, msg)
+	}
+	return newTaskError(ErrorKindUnknown, msg)
+}
+
+func errorKind(err error) string {
+	switch e := err.(type) {
+	case *taskError:
+		return e.kind
+	case net.Error:
+		return ErrorKindNetwork
+	}
+	return ErrorKindUnknown
+}
+
+// Retryable returns true if the kind of the err was declared in RetryOn, the default one was ErrorKindNetwork
+func (rc RetryConfig) Retryable(err error) bool {
+	if rc.MaxAttempts < 2 {
+		return false
+	}
+	kinds := rc.RetryOn
+	if len(kinds) == 0 {
+		kinds = []string{ErrorKindNetwork}
+	}
+	kind := errorKind(err)
+	for _, v := range kinds {
+		if v == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// commandRateLimiter dispatches the tasks to the rate limiters of their commands,
+// the commands without a configured backoff would use the workqueue.DefaultControllerRateLimiter
+type commandRateLimiter struct {
+	def      workqueue.RateLimiter
+	limiters map[string]workqueue.RateLimiter
+}
+
+func (r *commandRateLimiter) limiter(item interface{}) workqueue.RateLimiter {
+	if t, ok := item.(*Task); ok {
+		if l, ok := r.limiters[t.Command.Command]; ok {
+			return l
+		}
+	}
+	return r.def
+}
+
+func (r *commandRateLimiter) When(item interface{}) time.Duration {
+	return r.limiter(item).When(item)
+}
+
+func (r *commandRateLimiter) Forget(item interface{}) {
+	r.limiter(item).Forget(item)
+}
+
+func (r *commandRateLimiter) NumRequeues(item interface{}) int {
+	return r.limiter(item).NumRequeues(item)
+}
+
+func newCommandRateLimiter(commands map[string]CommandConfig) workqueue.RateLimiter {
+	r := &commandRateLimiter{
+		def:      workqueue.DefaultControllerRateLimiter(),
+		limiters: make(map[string]workqueue.RateLimiter, 0),
+	}
+	for k, v := range commands {
+		if v.Retry.BaseDelay <= 0 {
+			continue
+		}
+		maxDelay := v.Retry.MaxDelay
+		if maxDelay <= 0 {
+			maxDelay = defaultRetryMaxDelay
+		}
+		r.limiters[k] = workqueue.NewItemExponentialFailureRateLimiter(v.Retry.BaseDelay, maxDelay)
+	}
+	return r
+}
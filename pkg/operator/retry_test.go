@@ -0,0 +1,74 @@
+package operator
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryConfig_Retryable(t *testing.T) {
+	tests := []struct {
+		name   string
+		config RetryConfig
+		err    error
+		want   bool
+	}{
+		{
+			name:   "disabled",
+			config: RetryConfig{},
+			err:    newTaskError(ErrorKindNetwork, "ftp dial err"),
+			want:   false,
+		},
+		{
+			name:   "default network",
+			config: RetryConfig{MaxAttempts: 3},
+			err:    newTaskError(ErrorKindNetwork, "ftp dial err"),
+			want:   true,
+		},
+		{
+			name:   "default exit",
+			config: RetryConfig{MaxAttempts: 3},
+			err:    newTaskError(ErrorKindExit, "Git compress exec.Command err:exit status 1"),
+			want:   false,
+		},
+		{
+			name:   "declared exit",
+			config: RetryConfig{MaxAttempts: 3, RetryOn: []string{ErrorKindExit, ErrorKindTimeout}},
+			err:    newTaskError(ErrorKindExit, "Git compress exec.Command err:exit status 1"),
+			want:   true,
+		},
+		{
+			name:   "unknown",
+			config: RetryConfig{MaxAttempts: 3, RetryOn: []string{ErrorKindExit, ErrorKindTimeout}},
+			err:    errors.New("unknown"),
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.Retryable(tt.err); got != tt.want {
+				t.Errorf("RetryConfig.Retryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_commandRateLimiter(t *testing.T) {
+	r := newCommandRateLimiter(map[string]CommandConfig{
+		TaskCmdFtpUpload: {Retry: RetryConfig{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Second * 3}},
+	})
+	upload := &Task{Command: Command{Command: TaskCmdFtpUpload}}
+	for _, want := range []time.Duration{time.Second, time.Second * 2, time.Second * 3} {
+		if got := r.When(upload); got != want {
+			t.Errorf("commandRateLimiter.When() = %v, want %v", got, want)
+		}
+	}
+	r.Forget(upload)
+	if got := r.NumRequeues(upload); got != 0 {
+		t.Errorf("commandRateLimiter.NumRequeues() = %v, want 0", got)
+	}
+	gen := &Task{Command: Command{Command: TaskCmdGitGen}}
+	if got := r.When(gen); got != time.Millisecond*5 {
+		t.Errorf("commandRateLimiter.When() = %v, want the default 5ms", got)
+	}
+}
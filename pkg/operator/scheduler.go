@@ -0,0 +1,177 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"k8s.io/klog"
+)
+
+type Scheduler interface {
+	List() []Schedule
+	Pause(name string) error
+	Resume(name string) error
+	Trigger(name string) (id int, err error)
+}
+
+// Schedule is the state of a scheduled command
+// swagger:response Schedule
+type Schedule struct {
+	Name       string    `json:"name"`
+	Cron       string    `json:"cron"`
+	Command    Command   `json:"command"`
+	Paused     bool      `json:"paused"`
+	LastTaskId int       `json:"last_task_id"`
+	LastRun    time.Time `json:"last_run"`
+	NextRun    time.Time `json:"next_run"`
+	LastError  string    `json:"last_error"`
+}
+
+const (
+	errScheduleWasNotExisted = "the schedule: %s is not existed"
+	errScheduleInvalidCron   = "the schedule: %s has an invalid cron `%s` err:%v"
+	errSchedulePending       = "the schedule: %s skipped, the previous task:%d is still pending"
+)
+
+type scheduleEntry struct {
+	Schedule
+	entryId cron.EntryID
+}
+
+type scheduler struct {
+	mu sync.RWMutex
+
+	cron      *cron.Cron
+	schedules map[string]*scheduleEntry
+	tasks     *TaskHub
+	enqueue   func(c *Command) (id int, err error)
+	ctx       context.Context
+}
+
+func (s *scheduler) get(name string) (e *scheduleEntry, err error) {
+	if e, ok := s.schedules[name]; ok {
+		return e, nil
+	}
+	return e, errors.New(fmt.Sprintf(errScheduleWasNotExisted, name))
+}
+
+func (s *scheduler) List() []Schedule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := make([]Schedule, 0, len(s.schedules))
+	for _, v := range s.schedules {
+		sc := v.Schedule
+		if v.entryId != 0 && !v.Paused {
+			sc.NextRun = s.cron.Entry(v.entryId).Next
+		}
+		res = append(res, sc)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+func (s *scheduler) Pause(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, err := s.get(name)
+	if err != nil {
+		return err
+	}
+	e.Paused = true
+	return nil
+}
+
+func (s *scheduler) Resume(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, err := s.get(name)
+	if err != nil {
+		return err
+	}
+	e.Paused = false
+	return nil
+}
+
+func (s *scheduler) Trigger(name string) (id int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, err := s.get(name)
+	if err != nil {
+		return id, err
+	}
+	return s.run(e)
+}
+
+// run enqueues the command of the schedule unless the previous task of it was still pending
+func (s *scheduler) run(e *scheduleEntry) (id int, err error) {
+	if e.LastTaskId != 0 {
+		if t, err := s.tasks.Get(e.LastTaskId); err == nil && !isTaskFinished(t.status()) {
+			return id, errors.New(fmt.Sprintf(errSchedulePending, e.Name, e.LastTaskId))
+		}
+	}
+	c := e.Command
+	e.LastRun = time.Now()
+	if id, err = s.enqueue(&c); err != nil {
+		e.LastError = err.Error()
+		return id, err
+	}
+	e.LastTaskId = id
+	e.LastError = ""
+	return id, nil
+}
+
+func (s *scheduler) job(name string) func() {
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		e, err := s.get(name)
+		if err != nil || e.Paused {
+			return
+		}
+		if _, err := s.run(e); err != nil {
+			klog.V(2).Info(err)
+		}
+	}
+}
+
+func NewScheduler(conf ProjectConfig, tasks *TaskHub, enqueue func(c *Command) (id int, err error), ctx context.Context) Scheduler {
+	s := &scheduler{
+		cron:      cron.New(),
+		schedules: make(map[string]*scheduleEntry, 0),
+		tasks:     tasks,
+		enqueue:   enqueue,
+		ctx:       ctx,
+	}
+	for _, v := range conf.Schedules {
+		e := &scheduleEntry{
+			Schedule: Schedule{
+				Name:    v.Name,
+				Cron:    v.Cron,
+				Command: v.Command,
+			},
+		}
+		e.Command.ProjectName = conf.ProjectName
+		id, err := s.cron.AddFunc(v.Cron, s.job(v.Name))
+		if err != nil {
+			e.Paused = true
+			e.LastError = fmt.Sprintf(errScheduleInvalidCron, v.Name, v.Cron, err)
+			klog.V(2).Info(e.LastError)
+		}
+		e.entryId = id
+		s.schedules[v.Name] = e
+	}
+	s.cron.Start()
+	go func() {
+		<-s.ctx.Done()
+		s.cron.Stop()
+	}()
+	var sc Scheduler = s
+	return sc
+}
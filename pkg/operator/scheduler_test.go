@@ -0,0 +1,60 @@
+package operator
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewScheduler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tasks := NewTaskHub(NewMemoryStore(), ctx)
+	conf := ProjectConfig{
+		ProjectName: "projectName",
+		Schedules: []ScheduleConfig{
+			{Name: "nightly", Cron: "30 2 * * *", Command: Command{Command: TaskCmdFtpUpload, BranchName: "master"}},
+			{Name: "broken", Cron: "every night", Command: Command{Command: TaskCmdGitGen}},
+		},
+	}
+	enqueue := func(c *Command) (id int, err error) {
+		return tasks.NewTask(c).Id, nil
+	}
+	s := NewScheduler(conf, tasks, enqueue, ctx)
+
+	list := s.List()
+	if len(list) != 2 || list[0].Name != "broken" || list[1].Name != "nightly" {
+		t.Fatalf("Scheduler.List() = %v, want broken and nightly", list)
+	}
+	if !list[0].Paused || list[0].LastError == "" {
+		t.Errorf("the invalid schedule = %v, want paused with an error", list[0])
+	}
+	if list[1].NextRun.IsZero() {
+		t.Errorf("the next run of the nightly schedule was zero")
+	}
+
+	id, err := s.Trigger("nightly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, _ := tasks.Get(id)
+	if task.Command.ProjectName != "projectName" || task.Command.BranchName != "master" {
+		t.Errorf("the triggered command = %v", task.Command)
+	}
+	if _, err := s.Trigger("nightly"); err == nil {
+		t.Errorf("Scheduler.Trigger() error = nil, want the pending error")
+	}
+	task.ChangeStatus(TaskCompleted)
+	if _, err := s.Trigger("nightly"); err != nil {
+		t.Errorf("Scheduler.Trigger() error = %v after the previous task was completed", err)
+	}
+
+	if err := s.Pause("nightly"); err != nil {
+		t.Fatal(err)
+	}
+	if list := s.List(); !list[1].Paused || !list[1].NextRun.IsZero() {
+		t.Errorf("the paused schedule = %v", list[1])
+	}
+	if err := s.Resume("missing"); err == nil {
+		t.Errorf("Scheduler.Resume() error = nil, want the not existed error")
+	}
+}
@@ -0,0 +1,274 @@
+package operator
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Store persists the state which should survive a restart of go-gpt
+type Store interface {
+	LoadTasks() (max int, tasks []*Task, err error)
+	SaveTask(t *Task) error
+	DeleteTask(id int) error
+	// LastRelease returns the last version published from the branch
+	LastRelease(branch string) (r Release, ok bool, err error)
+	SaveRelease(r Release) error
+	// LoadSvnTags returns the svn tags which were set to the branches, keyed by the branch name
+	LoadSvnTags() (tags map[string]string, err error)
+	SaveSvnTag(branch, tag string) error
+	DeleteSvnTag(branch string) error
+	Close() error
+}
+
+const (
+	errStoreOpen      = "store open path:%s err:%v"
+	errStoreLoadTasks = "store load tasks err:%v"
+	errStoreSaveTask  = "store save task:%d err:%v"
+
+	errStoreLoadRelease = "store load the release of the branch:%s err:%v"
+	errStoreSaveRelease = "store save the release of the branch:%s err:%v"
+
+	errStoreLoadSvnTags = "store load svn tags err:%v"
+	errStoreSaveSvnTag  = "store save the svn tag of the branch:%s err:%v"
+)
+
+const (
+	storeOpenTimeout = time.Second * 3
+)
+
+var (
+	bucketTasks    = []byte("tasks")
+	bucketReleases = []byte("releases")
+	bucketSvnTags  = []byte("svn_tags")
+)
+
+// NewStore returns a bolt store when the path was configured, otherwise the tasks would be kept in memory only
+func NewStore(c StoreConfig) (Store, error) {
+	if c.Path == "" {
+		return NewMemoryStore(), nil
+	}
+	return NewBoltStore(c.Path)
+}
+
+// memoryStore keeps the releases and the svn tags until the exit, the tasks only live in the TaskHub
+type memoryStore struct {
+	mu       sync.RWMutex
+	releases map[string]Release
+	svnTags  map[string]string
+}
+
+func (ms *memoryStore) LoadTasks() (max int, tasks []*Task, err error) {
+	return 0, make([]*Task, 0), nil
+}
+
+func (ms *memoryStore) SaveTask(t *Task) error {
+	return nil
+}
+
+func (ms *memoryStore) DeleteTask(id int) error {
+	return nil
+}
+
+func (ms *memoryStore) LastRelease(branch string) (r Release, ok bool, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	r, ok = ms.releases[branch]
+	return r, ok, nil
+}
+
+func (ms *memoryStore) SaveRelease(r Release) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.releases[r.Branch] = r
+	return nil
+}
+
+func (ms *memoryStore) LoadSvnTags() (tags map[string]string, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	tags = make(map[string]string, len(ms.svnTags))
+	for k, v := range ms.svnTags {
+		tags[k] = v
+	}
+	return tags, nil
+}
+
+func (ms *memoryStore) SaveSvnTag(branch, tag string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.svnTags[branch] = tag
+	return nil
+}
+
+func (ms *memoryStore) DeleteSvnTag(branch string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.svnTags, branch)
+	return nil
+}
+
+func (ms *memoryStore) Close() error {
+	return nil
+}
+
+func NewMemoryStore() Store {
+	var s Store = &memoryStore{
+		releases: make(map[string]Release, 0),
+		svnTags:  make(map[string]string, 0),
+	}
+	return s
+}
+
+type boltStore struct {
+	db *bolt.DB
+}
+
+func itob(v int) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(v))
+	return b
+}
+
+func (bs *boltStore) LoadTasks() (max int, tasks []*Task, err error) {
+	tasks = make([]*Task, 0)
+	err = bs.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketTasks)
+		max = int(b.Sequence())
+		return b.ForEach(func(k, v []byte) error {
+			t := &Task{}
+			if err := json.Unmarshal(v, t); err != nil {
+				return err
+			}
+			tasks = append(tasks, t)
+			return nil
+		})
+	})
+	if err != nil {
+		return max, tasks, errors.New(fmt.Sprintf(errStoreLoadTasks, err))
+	}
+	return max, tasks, nil
+}
+
+func (bs *boltStore) SaveTask(t *Task) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return errors.New(fmt.Sprintf(errStoreSaveTask, t.Id, err))
+	}
+	err = bs.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketTasks)
+		if uint64(t.Id) > b.Sequence() {
+			if err := b.SetSequence(uint64(t.Id)); err != nil {
+				return err
+			}
+		}
+		return b.Put(itob(t.Id), data)
+	})
+	if err != nil {
+		return errors.New(fmt.Sprintf(errStoreSaveTask, t.Id, err))
+	}
+	return nil
+}
+
+func (bs *boltStore) DeleteTask(id int) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketTasks).Delete(itob(id))
+	})
+}
+
+func (bs *boltStore) LastRelease(branch string) (r Release, ok bool, err error) {
+	err = bs.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(bucketReleases).Get([]byte(branch))
+		if v == nil {
+			return nil
+		}
+		ok = true
+		return json.Unmarshal(v, &r)
+	})
+	if err != nil {
+		return r, false, errors.New(fmt.Sprintf(errStoreLoadRelease, branch, err))
+	}
+	return r, ok, nil
+}
+
+func (bs *boltStore) SaveRelease(r Release) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return errors.New(fmt.Sprintf(errStoreSaveRelease, r.Branch, err))
+	}
+	err = bs.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketReleases).Put([]byte(r.Branch), data)
+	})
+	if err != nil {
+		return errors.New(fmt.Sprintf(errStoreSaveRelease, r.Branch, err))
+	}
+	return nil
+}
+
+func (bs *boltStore) LoadSvnTags() (tags map[string]string, err error) {
+	tags = make(map[string]string, 0)
+	err = bs.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketSvnTags).ForEach(func(k, v []byte) error {
+			tags[string(k)] = string(v)
+			return nil
+		})
+	})
+	if err != nil {
+		return tags, errors.New(fmt.Sprintf(errStoreLoadSvnTags, err))
+	}
+	return tags, nil
+}
+
+func (bs *boltStore) SaveSvnTag(branch, tag string) error {
+	err := bs.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketSvnTags).Put([]byte(branch), []byte(tag))
+	})
+	if err != nil {
+		return errors.New(fmt.Sprintf(errStoreSaveSvnTag, branch, err))
+	}
+	return nil
+}
+
+func (bs *boltStore) DeleteSvnTag(branch string) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketSvnTags).Delete([]byte(branch))
+	})
+}
+
+func (bs *boltStore) Close() error {
+	return bs.db.Close()
+}
+
+func NewBoltStore(path string) (Store, error) {
+	// bolt creates the file only, the dirs of the path should be created first
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, errors.New(fmt.Sprintf(errStoreOpen, path, err))
+	}
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: storeOpenTimeout})
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf(errStoreOpen, path, err))
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, v := range [][]byte{bucketTasks, bucketReleases, bucketSvnTags} {
+			if _, err := tx.CreateBucketIfNotExists(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, errors.New(fmt.Sprintf(errStoreOpen, path, err))
+	}
+	var s Store = &boltStore{
+		db: db,
+	}
+	return s, nil
+}
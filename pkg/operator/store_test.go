@@ -0,0 +1,56 @@
+package operator
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewBoltStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-gpt-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// the missing dirs of the path would be created
+	path := filepath.Join(dir, "data", "tasks.db")
+
+	s, err := NewBoltStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	th := NewTaskHub(s, context.Background())
+	done := th.NewTask(&Command{ProjectName: "p", BranchName: "master", Command: TaskCmdGitGen})
+	done.ChangeStatus(TaskProcessing)
+	done.ChangeStatus(TaskCompleted)
+	running := th.NewTask(&Command{ProjectName: "p", BranchName: "dev", Command: TaskCmdFtpUpload})
+	running.ChangeStatus(TaskProcessing)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = NewBoltStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	th = NewTaskHub(s, context.Background())
+	if th.Max != 2 {
+		t.Errorf("TaskHub.Max = %d, want 2", th.Max)
+	}
+	all := th.GetAll()
+	if got := all[done.Id].Status; got != TaskCompleted {
+		t.Errorf("restored task %d status = %d, want %d", done.Id, got, TaskCompleted)
+	}
+	if got := all[running.Id].Status; got != TaskInterrupted {
+		t.Errorf("restored task %d status = %d, want %d", running.Id, got, TaskInterrupted)
+	}
+	if got := len(all[done.Id].Message); got != 2 {
+		t.Errorf("restored task %d messages = %d, want 2", done.Id, got)
+	}
+	if next := th.NewTask(&Command{ProjectName: "p"}); next.Id != 3 {
+		t.Errorf("next task id = %d, want 3", next.Id)
+	}
+}
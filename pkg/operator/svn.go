@@ -3,10 +3,9 @@ package operator
 import (
 	"context"
 	"encoding/xml"
-	"errors"
 	"fmt"
-	"os/exec"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,14 +16,25 @@ type SvnOperator interface {
 	Lock()
 	Unlock()
 	GetFullWorkDir() string
-	ExecuteWithArgs(args ...string) (res []byte, err error)
+	ExecuteWithArgs(ctx context.Context, args ...string) (res []byte, err error)
 	CheckOut() error
 	Update() error
 	Status() error
 	AddAll() error
 	Clean() error
-	Commit(svnMessage string) error
+	Commit(ctx context.Context, svnMessage string) error
+	StatusXml(ctx context.Context) (res []StatusEntry, err error)
+	AddPaths(ctx context.Context, paths ...string) error
+	DeletePaths(ctx context.Context, paths ...string) error
+	CommitPaths(ctx context.Context, svnMessage string, paths ...string) error
+	ReverseMerge(ctx context.Context, from, to int) error
+	Prepared() *SvnChangeSet
+	SetPrepared(cs *SvnChangeSet)
 	Log(number int) (res []Logentry, err error)
+	LogRange(limit int, revision, path string) (res []Logentry, err error)
+	Info(ctx context.Context) SvnInfo
+	Copy(ctx context.Context, src, dst, svnMessage string) error
+	List(ctx context.Context, url string) (res []SvnCopyEntry, err error)
 	Timer()
 	Listener(ch chan *Command)
 }
@@ -41,6 +51,16 @@ const (
 	cmdClean    = "clean"
 	cmdCommit   = "commit"
 	cmdLog      = "log"
+
+	cmdStatusXml   = "statusXml"
+	cmdAddPaths    = "addPaths"
+	cmdDeletePaths = "deletePaths"
+	cmdCommitPaths = "commitPaths"
+	cmdMerge       = "merge"
+	cmdLogRange    = "logRange"
+	cmdInfo        = "info"
+	cmdCopy        = "copy"
+	cmdList        = "list"
 )
 
 type svn struct {
@@ -58,7 +78,15 @@ type svn struct {
 	RemoteDir string `json:"remote_dir"`
 	SvnUrl    string `json:"svn_url"`
 
-	ctx context.Context
+	poll PollConfig
+	ctx  context.Context
+
+	preparedMu sync.Mutex
+	// prepared is the change set waiting to be confirmed or discarded, the working copy would not be updated until then
+	prepared *SvnChangeSet
+
+	healthMu sync.Mutex
+	health   svnHealth
 }
 
 func (s *svn) Lock() {
@@ -73,13 +101,14 @@ func (s *svn) GetFullWorkDir() string {
 	return fmt.Sprintf("%s/%s", s.WorkDir, s.RemoteDir)
 }
 
-func (s *svn) ExecuteWithArgs(args ...string) (res []byte, err error) {
+func (s *svn) ExecuteWithArgs(ctx context.Context, args ...string) (res []byte, err error) {
 	t := append([]string{s.ScriptPath, s.Username, s.Password, s.WorkDir, s.RemoteDir}, args...)
-	out, err := exec.Command("sh", t...).Output()
+	appendTaskMessage(ctx, fmt.Sprintf(execHeaderTemplate, svnScriptName, strings.Join(args, " ")))
+	out, err := execute(withRedacted(ctx, s.Password), "sh", t...)
 	if err != nil {
-		return out, errors.New(fmt.Sprintf("Svn %s exec.Command err:%v\n", args[0], err))
+		return out, newExecError(err, fmt.Sprintf("Svn %s exec.Command err:%v\n", args[0], err))
 	}
-	if args[0] != cmdLog {
+	if args[0] != cmdLog && args[0] != cmdLogRange && args[0] != cmdStatusXml && args[0] != cmdInfo && args[0] != cmdList {
 		klog.Infof("Svn Command `%s` output:\n%s\n", args[0], string(out))
 	}
 	return out, nil
@@ -88,7 +117,8 @@ func (s *svn) ExecuteWithArgs(args ...string) (res []byte, err error) {
 func (s *svn) CheckOut() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	_, err := s.ExecuteWithArgs(cmdCheckOut, s.SvnUrl)
+	_, err := s.ExecuteWithArgs(s.ctx, cmdCheckOut, s.SvnUrl)
+	s.setCheckedOut(err)
 	if err != nil {
 		return err
 	}
@@ -98,7 +128,8 @@ func (s *svn) CheckOut() error {
 func (s *svn) Update() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	_, err := s.ExecuteWithArgs(cmdUpdate)
+	_, err := s.ExecuteWithArgs(s.ctx, cmdUpdate)
+	s.setUpdated(err)
 	if err != nil {
 		return err
 	}
@@ -108,7 +139,7 @@ func (s *svn) Update() error {
 func (s *svn) Status() error {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	_, err := s.ExecuteWithArgs(cmdStatus)
+	_, err := s.ExecuteWithArgs(s.ctx, cmdStatus)
 	if err != nil {
 		return err
 	}
@@ -118,7 +149,7 @@ func (s *svn) Status() error {
 func (s *svn) AddAll() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	_, err := s.ExecuteWithArgs(cmdAddAll)
+	_, err := s.ExecuteWithArgs(s.ctx, cmdAddAll)
 	if err != nil {
 		return err
 	}
@@ -128,21 +159,84 @@ func (s *svn) AddAll() error {
 func (s *svn) Clean() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	_, err := s.ExecuteWithArgs(cmdClean)
+	_, err := s.ExecuteWithArgs(s.ctx, cmdClean)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *svn) Commit(message string) error {
-	_, err := s.ExecuteWithArgs(cmdCommit, message)
+func (s *svn) Commit(ctx context.Context, message string) error {
+	_, err := s.ExecuteWithArgs(ctx, cmdCommit, message)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+func (s *svn) StatusXml(ctx context.Context) (res []StatusEntry, err error) {
+	out, err := s.ExecuteWithArgs(ctx, cmdStatusXml)
+	if err != nil {
+		return res, err
+	}
+	rest := StatusResponse{}
+	if err := xml.Unmarshal(out, &rest); err != nil {
+		return res, err
+	}
+	return rest.Entries, nil
+}
+
+func (s *svn) AddPaths(ctx context.Context, paths ...string) error {
+	_, err := s.ExecuteWithArgs(ctx, append([]string{cmdAddPaths}, paths...)...)
+	return err
+}
+
+func (s *svn) DeletePaths(ctx context.Context, paths ...string) error {
+	_, err := s.ExecuteWithArgs(ctx, append([]string{cmdDeletePaths}, paths...)...)
+	return err
+}
+
+func (s *svn) CommitPaths(ctx context.Context, message string, paths ...string) error {
+	_, err := s.ExecuteWithArgs(ctx, append([]string{cmdCommitPaths, message}, paths...)...)
+	return err
+}
+
+// ReverseMerge undoes the revisions from..to in the working copy without committing them
+func (s *svn) ReverseMerge(ctx context.Context, from, to int) error {
+	_, err := s.ExecuteWithArgs(ctx, append([]string{cmdMerge}, reverseMergeArgs(from, to)...)...)
+	return err
+}
+
+// Copy creates the server-side copy, it would fail if the dst was existed
+func (s *svn) Copy(ctx context.Context, src, dst, message string) error {
+	_, err := s.ExecuteWithArgs(ctx, cmdCopy, src, dst, message)
+	return err
+}
+
+func (s *svn) List(ctx context.Context, url string) (res []SvnCopyEntry, err error) {
+	out, err := s.ExecuteWithArgs(ctx, cmdList, url)
+	if err != nil {
+		return res, err
+	}
+	rest := ListResponse{}
+	if err := xml.Unmarshal(out, &rest); err != nil {
+		return res, err
+	}
+	return rest.Entries, nil
+}
+
+func (s *svn) Prepared() *SvnChangeSet {
+	s.preparedMu.Lock()
+	defer s.preparedMu.Unlock()
+	return s.prepared
+}
+
+func (s *svn) SetPrepared(cs *SvnChangeSet) {
+	s.preparedMu.Lock()
+	defer s.preparedMu.Unlock()
+	s.prepared = cs
+}
+
 type LogResponse struct {
 	XMLName   xml.Name   `xml:"log"`
 	Logentrys []Logentry `xml:"logentry" json:"logentrys"`
@@ -154,6 +248,10 @@ type Logentry struct {
 	DateTime time.Time `xml:"date" json:"date_time,omitempty"`
 	Msg      string    `xml:"msg" json:"msg,omitempty"`
 	Paths    []Path    `xml:"paths>path" json:"paths,omitempty"`
+	// RolledBackBy is the revision of the rollback commit which undid this one
+	RolledBackBy string `xml:"-" json:"rolled_back_by,omitempty"`
+	// RollbackOf are the revisions undone by this rollback commit
+	RollbackOf []string `xml:"-" json:"rollback_of,omitempty"`
 }
 
 type Path struct {
@@ -167,7 +265,7 @@ type Path struct {
 func (s *svn) Log(number int) (res []Logentry, err error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	out, err := s.ExecuteWithArgs(cmdLog, strconv.Itoa(number))
+	out, err := s.ExecuteWithArgs(s.ctx, cmdLog, strconv.Itoa(number))
 	if err != nil {
 		return res, err
 	}
@@ -175,20 +273,48 @@ func (s *svn) Log(number int) (res []Logentry, err error) {
 	if err := xml.Unmarshal(out, &rest); err != nil {
 		return res, err
 	}
+	annotateRollbacks(rest.Logentrys)
+	return rest.Logentrys, nil
+}
+
+// LogRange returns the log of the revision range (e.g. HEAD:1), it would be scoped to the path if it was not empty
+func (s *svn) LogRange(limit int, revision, path string) (res []Logentry, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	args := []string{cmdLogRange, strconv.Itoa(limit), revision}
+	if path != "" {
+		args = append(args, path)
+	}
+	out, err := s.ExecuteWithArgs(s.ctx, args...)
+	if err != nil {
+		return res, err
+	}
+	rest := LogResponse{}
+	if err := xml.Unmarshal(out, &rest); err != nil {
+		return res, err
+	}
+	annotateRollbacks(rest.Logentrys)
 	return rest.Logentrys, nil
 }
 
 func (s *svn) Timer() {
-	tick := time.NewTicker(time.Second * 10)
-	defer tick.Stop()
+	poll := newPoller(s.poll)
+	defer poll.Stop()
 	for {
 		select {
 		case <-s.ctx.Done():
 			return
-		case <-tick.C:
-			if err := s.Update(); err != nil {
+		case <-poll.C():
+			// the update would clean the prepared change set
+			if s.Prepared() != nil {
+				poll.Reset(nil)
+				continue
+			}
+			err := s.Update()
+			if err != nil {
 				klog.V(2).Info(err)
 			}
+			poll.Reset(err)
 		}
 	}
 }
@@ -202,7 +328,7 @@ func (s *svn) Listener(ch chan *Command) {
 				return
 			}
 			klog.Infof("cmd:%v", *c)
-			if _, err := s.ExecuteWithArgs(c.Command, c.Message); err != nil {
+			if _, err := s.ExecuteWithArgs(s.ctx, c.Command, c.Message); err != nil {
 				klog.V(2).Infof("Listener exc err:%v", err)
 			}
 		case <-s.ctx.Done():
@@ -222,6 +348,7 @@ func NewSvnOperator(v *ProjectConfig, ctx context.Context) SvnOperator {
 		Port:        v.Svn.Port,
 		RemoteDir:   v.Svn.RemoteDir,
 		SvnUrl:      fmt.Sprintf(svnUrl, v.Svn.Username, v.Svn.Url, v.Svn.Port, v.Svn.RemoteDir),
+		poll:        v.Svn.Poll,
 		ctx:         ctx,
 	}
 	if err := svn.CheckOut(); err != nil {
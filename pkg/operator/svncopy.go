@@ -0,0 +1,178 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// the kinds of the server-side copies of the RemoteDir
+const (
+	SvnCopyTag    = "tag"
+	SvnCopyBranch = "branch"
+)
+
+const (
+	errSvnCopyKind = "the kind `%s` of the svn copy is unknown, it should be tag or branch"
+	errSvnCopyName = "the name `%s` of the svn %s is invalid"
+
+	msgSvnCopy = "create the %s %s"
+
+	svnServerTemplate = "svn://%s:%d/%s"
+
+	defaultSvnCopyTemplate = "{{.Date}}{{.Version}}"
+)
+
+var svnCopyDirs = map[string]string{
+	SvnCopyTag:    "tags",
+	SvnCopyBranch: "branches",
+}
+
+type ListResponse struct {
+	XMLName xml.Name       `xml:"lists"`
+	Entries []SvnCopyEntry `xml:"list>entry"`
+}
+
+// SvnCopyEntry is a tag or a branch of the svn
+type SvnCopyEntry struct {
+	Kind   string `xml:"kind,attr" json:"-"`
+	Name   string `xml:"name" json:"name"`
+	Commit struct {
+		Revision string    `xml:"revision,attr" json:"revision"`
+		Author   string    `xml:"author" json:"author"`
+		Date     time.Time `xml:"date" json:"date"`
+	} `xml:"commit" json:"commit"`
+}
+
+// svnCopyData is rendered by SvnCopyConfig.Template
+type svnCopyData struct {
+	Date    string
+	Version string
+	Branch  string
+}
+
+// svnCopyDir returns the parent of the copies relative to the root of the svn server,
+// the default one is beside the RemoteDir, e.g. projectName/tags of projectName/trunk
+func svnCopyDir(conf SvnConfig, kind string) (dir string, err error) {
+	var cc SvnCopyConfig
+	switch kind {
+	case SvnCopyTag:
+		cc = conf.Tags
+	case SvnCopyBranch:
+		cc = conf.Branches
+	default:
+		return dir, errors.New(fmt.Sprintf(errSvnCopyKind, kind))
+	}
+	if cc.Dir != "" {
+		return strings.Trim(cc.Dir, "/"), nil
+	}
+	parent := path.Dir(strings.Trim(conf.RemoteDir, "/"))
+	if parent == "." {
+		return svnCopyDirs[kind], nil
+	}
+	return path.Join(parent, svnCopyDirs[kind]), nil
+}
+
+func svnCopyTemplate(conf SvnConfig, kind string) string {
+	text := conf.Tags.Template
+	if kind == SvnCopyBranch {
+		text = conf.Branches.Template
+	}
+	if text == "" {
+		text = defaultSvnCopyTemplate
+	}
+	return text
+}
+
+func renderSvnCopyName(text string, data svnCopyData) (res string, err error) {
+	tpl, err := template.New("svnCopy").Parse(text)
+	if err != nil {
+		return res, err
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return res, err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// validSvnCopyName accepts a single path segment, the slashes of the branch names should be replaced in the template
+func validSvnCopyName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, "/\\")
+}
+
+func svnServerUrl(conf SvnConfig, p string) string {
+	return fmt.Sprintf(svnServerTemplate, conf.Url, conf.Port, strings.Trim(p, "/"))
+}
+
+// SvnCopy creates the tag or the branch by the server-side copy of the RemoteDir,
+// the name would be rendered by the template of the config if it was empty
+func (ph *projects) SvnCopy(ctx context.Context, projectName, kind, branchName, name, svnMessage string) error {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return err
+	}
+	conf := p.conf.Svn
+	dir, err := svnCopyDir(conf, kind)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		text := svnCopyTemplate(conf, kind)
+		data := svnCopyData{
+			Date:   time.Now().Format("20060102"),
+			Branch: branchName,
+		}
+		// the ftp was only required by the templates using the version
+		if strings.Contains(text, ".Version") {
+			if data.Version, err = p.ftp.GetNextVersion(); err != nil {
+				return err
+			}
+		}
+		if name, err = renderSvnCopyName(text, data); err != nil {
+			return err
+		}
+	}
+	if !validSvnCopyName(name) {
+		return errors.New(fmt.Sprintf(errSvnCopyName, name, kind))
+	}
+	message := fmt.Sprintf(msgSvnCopy, kind, name)
+	if svnMessage != "" {
+		message = fmt.Sprintf("%s: %s", message, svnMessage)
+	}
+	appendTaskMessage(ctx, message)
+	return p.svn.Copy(ctx, svnServerUrl(conf, conf.RemoteDir), svnServerUrl(conf, path.Join(dir, name)), message)
+}
+
+// SvnCopies lists the tags or the branches, the latest created one comes first
+func (ph *projects) SvnCopies(projectName, kind string) (res []SvnCopyEntry, err error) {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return res, err
+	}
+	dir, err := svnCopyDir(p.conf.Svn, kind)
+	if err != nil {
+		return res, err
+	}
+	entries, err := p.svn.List(p.ctx, svnServerUrl(p.conf.Svn, dir))
+	if err != nil {
+		return res, err
+	}
+	res = make([]SvnCopyEntry, 0, len(entries))
+	for _, v := range entries {
+		if v.Kind == "dir" {
+			res = append(res, v)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return revisionAfter(res[i].Commit.Revision, res[j].Commit.Revision)
+	})
+	return res, nil
+}
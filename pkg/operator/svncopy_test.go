@@ -0,0 +1,71 @@
+package operator
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func Test_svnCopyDir(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    SvnConfig
+		kind    string
+		want    string
+		wantErr bool
+	}{
+		{name: "beside trunk", conf: SvnConfig{RemoteDir: "projectName/trunk"}, kind: SvnCopyTag, want: "projectName/tags"},
+		{name: "root", conf: SvnConfig{RemoteDir: "projectName_dir"}, kind: SvnCopyBranch, want: "branches"},
+		{name: "configured", conf: SvnConfig{RemoteDir: "projectName_dir", Tags: SvnCopyConfig{Dir: "/releases/tags/"}}, kind: SvnCopyTag, want: "releases/tags"},
+		{name: "unknown", conf: SvnConfig{}, kind: "trunk", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svnCopyDir(tt.conf, tt.kind)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("svnCopyDir() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("svnCopyDir() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+	if got := svnServerUrl(SvnConfig{Url: "192.168.1.1", Port: 3690}, "projectName/tags/2021030101"); got != "svn://192.168.1.1:3690/projectName/tags/2021030101" {
+		t.Errorf("svnServerUrl() = %s", got)
+	}
+}
+
+func Test_renderSvnCopyName(t *testing.T) {
+	data := svnCopyData{Date: "20210301", Version: "02", Branch: "release/1.2"}
+	if got, err := renderSvnCopyName(defaultSvnCopyTemplate, data); err != nil || got != "2021030102" {
+		t.Errorf("renderSvnCopyName() = %s, %v, want 2021030102", got, err)
+	}
+	got, err := renderSvnCopyName(`{{.Branch}}-{{.Date}}`, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if validSvnCopyName(got) {
+		t.Errorf("validSvnCopyName(%s) = true, want false for the slash", got)
+	}
+	got, err = renderSvnCopyName(`{{replace .Branch}}`, data)
+	if err == nil {
+		t.Errorf("renderSvnCopyName() = %s, want the error of the undefined function", got)
+	}
+}
+
+func Test_ListResponse(t *testing.T) {
+	out := `<?xml version="1.0" encoding="UTF-8"?>
+<lists>
+<list path="svn://192.168.1.1:3690/projectName/tags">
+<entry kind="dir"><name>2021030101</name><commit revision="120"><author>bob</author><date>2021-03-01T08:00:00.000000Z</date></commit></entry>
+<entry kind="file"><name>README</name><commit revision="3"><author>bob</author><date>2021-01-01T08:00:00.000000Z</date></commit></entry>
+</list>
+</lists>`
+	rest := ListResponse{}
+	if err := xml.Unmarshal([]byte(out), &rest); err != nil {
+		t.Fatal(err)
+	}
+	if len(rest.Entries) != 2 || rest.Entries[0].Name != "2021030101" || rest.Entries[0].Kind != "dir" ||
+		rest.Entries[0].Commit.Revision != "120" || rest.Entries[0].Commit.Date.IsZero() {
+		t.Errorf("ListResponse = %+v, want the tag 2021030101 at r120", rest.Entries)
+	}
+}
@@ -0,0 +1,125 @@
+package operator
+
+import (
+	"context"
+	"encoding/xml"
+	"time"
+)
+
+const (
+	// the working copy would be unhealthy after the updates were failed for svnUnhealthyFailures times in a row
+	svnUnhealthyFailures = 3
+)
+
+type InfoResponse struct {
+	XMLName xml.Name  `xml:"info"`
+	Entry   InfoEntry `xml:"entry"`
+}
+
+type InfoEntry struct {
+	Kind           string `xml:"kind,attr"`
+	Revision       string `xml:"revision,attr"`
+	Url            string `xml:"url"`
+	RelativeUrl    string `xml:"relative-url"`
+	RepositoryRoot string `xml:"repository>root"`
+	Schedule       string `xml:"wc-info>schedule"`
+	Commit         struct {
+		Revision string    `xml:"revision,attr"`
+		Author   string    `xml:"author"`
+		Date     time.Time `xml:"date"`
+	} `xml:"commit"`
+}
+
+// SvnInfo is the info of the svn working copy and the results of its checkout and updates
+// swagger:response SvnInfo
+type SvnInfo struct {
+	Url                 string    `json:"url"`
+	RelativeUrl         string    `json:"relative_url"`
+	RepositoryRoot      string    `json:"repository_root"`
+	Revision            string    `json:"revision"`
+	LastChangedRevision string    `json:"last_changed_revision"`
+	LastChangedAuthor   string    `json:"last_changed_author"`
+	LastChangedDate     time.Time `json:"last_changed_date"`
+	// InfoError is the error of `svn info`, e.g. the working copy was not checked out
+	InfoError     string `json:"info_error,omitempty"`
+	CheckOutError string `json:"check_out_error,omitempty"`
+	// LastUpdate is the time of the last update, UpdateFailures is the number of the failures in a row
+	LastUpdate      time.Time `json:"last_update"`
+	LastUpdateError string    `json:"last_update_error,omitempty"`
+	UpdateFailures  int       `json:"update_failures"`
+	// Prepared is true if there was a change set waiting to be confirmed or discarded, the updates were paused
+	Prepared bool `json:"prepared"`
+	Healthy  bool `json:"healthy"`
+}
+
+// svnHealth records the results of the checkout and the updates
+type svnHealth struct {
+	checkOutErr    error
+	lastUpdate     time.Time
+	lastUpdateErr  error
+	updateFailures int
+}
+
+func (s *svn) setCheckedOut(err error) {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	s.health.checkOutErr = err
+}
+
+func (s *svn) setUpdated(err error) {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	s.health.lastUpdate = time.Now()
+	s.health.lastUpdateErr = err
+	if err != nil {
+		s.health.updateFailures++
+	} else {
+		s.health.updateFailures = 0
+	}
+}
+
+// Info runs `svn info` of the working copy, its error was recorded in the SvnInfo instead of being returned
+func (s *svn) Info(ctx context.Context) SvnInfo {
+	s.mu.RLock()
+	out, err := s.ExecuteWithArgs(ctx, cmdInfo)
+	s.mu.RUnlock()
+	res := SvnInfo{}
+	if err == nil {
+		rest := InfoResponse{}
+		if err = xml.Unmarshal(out, &rest); err == nil {
+			e := rest.Entry
+			res.Url = e.Url
+			res.RelativeUrl = e.RelativeUrl
+			res.RepositoryRoot = e.RepositoryRoot
+			res.Revision = e.Revision
+			res.LastChangedRevision = e.Commit.Revision
+			res.LastChangedAuthor = e.Commit.Author
+			res.LastChangedDate = e.Commit.Date
+		}
+	}
+	if err != nil {
+		res.InfoError = err.Error()
+	}
+	s.healthMu.Lock()
+	h := s.health
+	s.healthMu.Unlock()
+	if h.checkOutErr != nil {
+		res.CheckOutError = h.checkOutErr.Error()
+	}
+	res.LastUpdate = h.lastUpdate
+	if h.lastUpdateErr != nil {
+		res.LastUpdateError = h.lastUpdateErr.Error()
+	}
+	res.UpdateFailures = h.updateFailures
+	res.Prepared = s.Prepared() != nil
+	res.Healthy = err == nil && h.updateFailures < svnUnhealthyFailures
+	return res
+}
+
+func (ph *projects) SvnInfo(projectName string) (res SvnInfo, err error) {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return res, err
+	}
+	return p.svn.Info(p.ctx), nil
+}
@@ -0,0 +1,53 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testInfoScript = `cat <<EOF
+<?xml version="1.0" encoding="UTF-8"?>
+<info>
+<entry kind="dir" path="." revision="123">
+<url>svn://192.168.1.1:3690/projectName_dir</url>
+<relative-url>^/projectName_dir</relative-url>
+<repository><root>svn://192.168.1.1:3690</root><uuid>uuid</uuid></repository>
+<wc-info><schedule>normal</schedule><depth>infinity</depth></wc-info>
+<commit revision="120"><author>bob</author><date>2021-03-01T08:00:00.000000Z</date></commit>
+</entry>
+</info>
+EOF
+`
+
+func Test_svn_Info(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-gpt-svn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	script := filepath.Join(dir, svnScriptName)
+	if err := ioutil.WriteFile(script, []byte(testInfoScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	s := &svn{ScriptPath: script, ctx: context.Background()}
+	info := s.Info(context.Background())
+	if info.InfoError != "" || info.Revision != "123" || info.LastChangedAuthor != "bob" ||
+		info.RepositoryRoot != "svn://192.168.1.1:3690" || info.LastChangedDate.IsZero() || !info.Healthy {
+		t.Fatalf("Info() = %+v, want the healthy working copy at r123", info)
+	}
+	for i := 0; i < svnUnhealthyFailures; i++ {
+		s.setUpdated(errors.New("connection refused"))
+	}
+	if info := s.Info(context.Background()); info.Healthy || info.UpdateFailures != svnUnhealthyFailures || info.LastUpdateError == "" {
+		t.Errorf("Info() = %+v, want unhealthy after the failed updates", info)
+	}
+	s.setUpdated(nil)
+	s.ScriptPath = filepath.Join(dir, "missing.sh")
+	if info := s.Info(context.Background()); info.Healthy || info.InfoError == "" || info.UpdateFailures != 0 {
+		t.Errorf("Info() = %+v, want unhealthy with the info error", info)
+	}
+}
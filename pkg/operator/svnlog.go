@@ -0,0 +1,153 @@
+package operator
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	errSvnLogPath = "the svn log path `%s` should be relative to the working copy"
+
+	svnRevisionHead  = "HEAD"
+	svnRevisionRange = "%s:%d"
+
+	defaultSvnLogLimit = 20
+	maxSvnLogLimit     = 200
+	// the entries were fetched batch by batch and filtered, the scan stops after maxSvnLogScans batches,
+	// and SvnLogPage.Next would continue it
+	svnLogBatchSize = 100
+	maxSvnLogScans  = 10
+)
+
+// SvnLogFilter filters the svn log by the fields which were not empty, the revisions were inclusive
+type SvnLogFilter struct {
+	Author       string
+	Since        time.Time
+	Until        time.Time
+	FromRevision int
+	ToRevision   int
+	// Message is a case-insensitive substring of the log message
+	Message string
+	// Path is a file or a dir relative to the working copy, only the revisions changing it would be returned
+	Path string
+	// Before pages the log, only the revisions lower than it would be returned
+	Before int
+	Limit  int
+}
+
+// SvnLogPage
+// swagger:response SvnLogPage
+type SvnLogPage struct {
+	Entries []Logentry `json:"entries"`
+	// Next is the Before of the next page, it was zero if there were no more entries
+	Next int `json:"next"`
+}
+
+func (f *SvnLogFilter) match(v Logentry) bool {
+	if f.Author != "" && v.Author != f.Author {
+		return false
+	}
+	if !f.Until.IsZero() && v.DateTime.After(f.Until) {
+		return false
+	}
+	if f.Message != "" && !strings.Contains(strings.ToLower(v.Msg), strings.ToLower(f.Message)) {
+		return false
+	}
+	return true
+}
+
+// start returns the revision where the scan starts from
+func (f *SvnLogFilter) start() (rev int, head bool) {
+	rev = f.ToRevision
+	if f.Before > 0 && (rev == 0 || f.Before-1 < rev) {
+		rev = f.Before - 1
+	}
+	return rev, rev == 0 && f.Before == 0
+}
+
+func validSvnLogPath(p string) bool {
+	if p == "" {
+		return true
+	}
+	if path.IsAbs(p) {
+		return false
+	}
+	for _, v := range strings.Split(path.Clean(p), "/") {
+		if v == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// scanSvnLog fetches the log from the newest revision batch by batch until the page was filled
+func scanSvnLog(f SvnLogFilter, fetch func(limit int, revision string) ([]Logentry, error)) (page SvnLogPage, err error) {
+	if f.Limit < 1 {
+		f.Limit = defaultSvnLogLimit
+	}
+	if f.Limit > maxSvnLogLimit {
+		f.Limit = maxSvnLogLimit
+	}
+	page.Entries = make([]Logentry, 0, f.Limit)
+	end := f.FromRevision
+	if end < 1 {
+		end = 1
+	}
+	rev, head := f.start()
+	if !head && rev < end {
+		return page, nil
+	}
+	start := svnRevisionHead
+	if !head {
+		start = strconv.Itoa(rev)
+	}
+	last := 0
+	for i := 0; i < maxSvnLogScans; i++ {
+		entries, err := fetch(svnLogBatchSize, fmt.Sprintf(svnRevisionRange, start, end))
+		if err != nil {
+			return page, err
+		}
+		for _, v := range entries {
+			r, err := strconv.Atoi(v.Revision)
+			if err != nil {
+				continue
+			}
+			last = r
+			// the log was sorted by the time descending
+			if !f.Since.IsZero() && v.DateTime.Before(f.Since) {
+				return page, nil
+			}
+			if !f.match(v) {
+				continue
+			}
+			page.Entries = append(page.Entries, v)
+			if len(page.Entries) == f.Limit {
+				page.Next = r
+				return page, nil
+			}
+		}
+		if len(entries) < svnLogBatchSize || last <= end {
+			return page, nil
+		}
+		start = strconv.Itoa(last - 1)
+	}
+	page.Next = last
+	return page, nil
+}
+
+func (ph *projects) SvnLogList(projectName string, filter SvnLogFilter) (res SvnLogPage, err error) {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return res, err
+	}
+	if !validSvnLogPath(filter.Path) {
+		return res, errors.New(fmt.Sprintf(errSvnLogPath, filter.Path))
+	}
+	return scanSvnLog(filter, func(limit int, revision string) ([]Logentry, error) {
+		return p.svn.LogRange(limit, revision, filter.Path)
+	})
+}
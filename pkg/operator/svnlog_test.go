@@ -0,0 +1,74 @@
+package operator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSvnLog serves the revisions 1..head, the even ones were committed by alice
+func fakeSvnLog(head int, base time.Time, calls *int) func(limit int, revision string) ([]Logentry, error) {
+	return func(limit int, revision string) ([]Logentry, error) {
+		*calls++
+		fields := strings.SplitN(revision, ":", 2)
+		start := head
+		if fields[0] != svnRevisionHead {
+			start, _ = strconv.Atoi(fields[0])
+		}
+		end, _ := strconv.Atoi(fields[1])
+		res := make([]Logentry, 0)
+		for r := start; r >= end && len(res) < limit; r-- {
+			author := "bob"
+			if r%2 == 0 {
+				author = "alice"
+			}
+			res = append(res, Logentry{
+				Revision: strconv.Itoa(r),
+				Author:   author,
+				DateTime: base.Add(time.Duration(r) * time.Hour),
+				Msg:      fmt.Sprintf("Sync r%d", r),
+			})
+		}
+		return res, nil
+	}
+}
+
+func Test_scanSvnLog(t *testing.T) {
+	base := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	var calls int
+	fetch := fakeSvnLog(250, base, &calls)
+	page, err := scanSvnLog(SvnLogFilter{Author: "alice", Limit: 60}, fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Entries) != 60 || page.Entries[0].Revision != "250" || page.Next != 132 {
+		t.Fatalf("scanSvnLog() = %d entries from %s, next %d, want 60 from 250, next 132", len(page.Entries), page.Entries[0].Revision, page.Next)
+	}
+	page, err = scanSvnLog(SvnLogFilter{Author: "alice", Limit: 60, Before: page.Next}, fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Entries) != 60 || page.Entries[0].Revision != "130" || page.Next != 12 {
+		t.Errorf("scanSvnLog() = %d entries from %s, next %d, want 60 from 130, next 12", len(page.Entries), page.Entries[0].Revision, page.Next)
+	}
+	page, err = scanSvnLog(SvnLogFilter{FromRevision: 100, ToRevision: 120, Message: "SYNC R11", Since: base.Add(112 * time.Hour)}, fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Entries) != 8 || page.Entries[0].Revision != "119" || page.Entries[7].Revision != "112" || page.Next != 0 {
+		t.Errorf("scanSvnLog() = %v, next %d, want r119..r112", page.Entries, page.Next)
+	}
+	calls = 0
+	page, err = scanSvnLog(SvnLogFilter{Author: "carol"}, fakeSvnLog(5000, base, &calls))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Entries) != 0 || calls != maxSvnLogScans || page.Next != 5000-maxSvnLogScans*svnLogBatchSize+1 {
+		t.Errorf("scanSvnLog() = %d entries after %d scans, next %d, want the scan to stop", len(page.Entries), calls, page.Next)
+	}
+	if validSvnLogPath("../etc") || validSvnLogPath("/etc") || !validSvnLogPath("data/config") {
+		t.Errorf("validSvnLogPath() should accept the relative paths in the working copy only")
+	}
+}
@@ -0,0 +1,257 @@
+package operator
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/klog"
+)
+
+const (
+	errSvnPrepared         = "the svn change set #%d of the branch `%s` was prepared, it should be confirmed or discarded first"
+	errSvnNotPrepared      = "the svn change set #%d was not prepared"
+	errSvnChangeSetDrifted = "the svn working copy was changed after the change set #%d was prepared, it should be prepared again"
+	errSvnMessageEmpty     = "the svn message of the change set was empty"
+
+	msgSvnPrepared  = "prepared the svn change set #%d: %d added, %d modified, %d deleted"
+	msgSvnConfirmed = "confirmed the svn change set #%d: %d paths committed"
+)
+
+// the items of the wc-status of `svn status --xml`
+const (
+	SvnItemUnversioned = "unversioned"
+	SvnItemAdded       = "added"
+	SvnItemModified    = "modified"
+	SvnItemReplaced    = "replaced"
+	SvnItemDeleted     = "deleted"
+	SvnItemMissing     = "missing"
+	SvnItemConflicted  = "conflicted"
+	SvnItemNormal      = "normal"
+)
+
+type StatusResponse struct {
+	XMLName xml.Name      `xml:"status"`
+	Entries []StatusEntry `xml:"target>entry"`
+}
+
+type StatusEntry struct {
+	Path     string   `xml:"path,attr"`
+	WcStatus WcStatus `xml:"wc-status"`
+}
+
+type WcStatus struct {
+	Item  string `xml:"item,attr"`
+	Props string `xml:"props,attr"`
+}
+
+// SvnChange is a changed path of the svn working copy, the Size is zero for the dirs and the deleted files
+type SvnChange struct {
+	Path string `json:"path"`
+	Item string `json:"item"`
+	Dir  bool   `json:"dir"`
+	Size int64  `json:"size"`
+}
+
+// SvnChangeSet is the change set synchronized from the branch which was waiting to be confirmed or discarded,
+// the Id is the id of the prepare task
+// swagger:response SvnChangeSet
+type SvnChangeSet struct {
+	Id         int         `json:"id"`
+	Branch     string      `json:"branch"`
+	Message    string      `json:"message"`
+	Changes    []SvnChange `json:"changes"`
+	Added      int         `json:"added"`
+	Modified   int         `json:"modified"`
+	Deleted    int         `json:"deleted"`
+	PreparedAt time.Time   `json:"prepared_at"`
+}
+
+// svnChanges converts the entries of the svn status, the unchanged and the ignored ones were skipped
+func svnChanges(workDir string, entries []StatusEntry) []SvnChange {
+	res := make([]SvnChange, 0, len(entries))
+	for _, v := range entries {
+		item := v.WcStatus.Item
+		switch item {
+		case SvnItemUnversioned, SvnItemAdded, SvnItemModified, SvnItemReplaced,
+			SvnItemDeleted, SvnItemMissing, SvnItemConflicted:
+		case SvnItemNormal:
+			// the properties were changed only
+			if v.WcStatus.Props != SvnItemModified {
+				continue
+			}
+			item = SvnItemModified
+		default:
+			continue
+		}
+		c := SvnChange{Path: v.Path, Item: item}
+		if fi, err := os.Stat(filepath.Join(workDir, v.Path)); err == nil {
+			c.Dir = fi.IsDir()
+			if !c.Dir {
+				c.Size = fi.Size()
+			}
+		}
+		res = append(res, c)
+	}
+	return res
+}
+
+func (cs *SvnChangeSet) count() {
+	cs.Added, cs.Modified, cs.Deleted = 0, 0, 0
+	for _, v := range cs.Changes {
+		switch v.Item {
+		case SvnItemUnversioned, SvnItemAdded:
+			cs.Added++
+		case SvnItemDeleted, SvnItemMissing:
+			cs.Deleted++
+		default:
+			cs.Modified++
+		}
+	}
+}
+
+// sameChanges compares the paths and the items, the sizes were ignored because they were not recorded by svn
+func sameChanges(a, b []SvnChange) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	items := make(map[string]string, len(a))
+	for _, v := range a {
+		items[v.Path] = v.Item
+	}
+	for _, v := range b {
+		if item, ok := items[v.Path]; !ok || item != v.Item {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *project) svnChanges(ctx context.Context) (res []SvnChange, err error) {
+	entries, err := p.svn.StatusXml(ctx)
+	if err != nil {
+		return res, err
+	}
+	return svnChanges(p.svn.GetFullWorkDir(), entries), nil
+}
+
+// SvnPrepare synchronizes the branch into the svn working copy, and keeps the change set until it was confirmed or discarded
+func (ph *projects) SvnPrepare(ctx context.Context, projectName, branchName, svnMessage string) error {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return err
+	}
+	if svnMessage == "" {
+		return errors.New(errSvnMessageEmpty)
+	}
+	if err := p.git.CheckBranch(branchName, false); err != nil {
+		return err
+	}
+	p.svn.Lock()
+	defer p.svn.Unlock()
+	if cs := p.svn.Prepared(); cs != nil {
+		return errors.New(fmt.Sprintf(errSvnPrepared, cs.Id, cs.Branch))
+	}
+	if err := p.git.SvnSync(ctx, branchName, p.svn.GetFullWorkDir()); err != nil {
+		return err
+	}
+	changes, err := p.svnChanges(ctx)
+	if err != nil {
+		return err
+	}
+	cs := &SvnChangeSet{
+		Branch:     branchName,
+		Message:    svnMessage,
+		Changes:    changes,
+		PreparedAt: time.Now(),
+	}
+	if t, ok := taskFromContext(ctx); ok {
+		cs.Id = t.Id
+	}
+	cs.count()
+	p.svn.SetPrepared(cs)
+	appendTaskMessage(ctx, fmt.Sprintf(msgSvnPrepared, cs.Id, cs.Added, cs.Modified, cs.Deleted))
+	return nil
+}
+
+func (ph *projects) SvnPrepared(projectName string) (res *SvnChangeSet, err error) {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return res, err
+	}
+	return p.svn.Prepared(), nil
+}
+
+// SvnConfirm commits exactly the paths of the prepared change set by the svnConfirm task,
+// it would be rejected if the working copy was changed since then
+func (ph *projects) SvnConfirm(ctx context.Context, projectName string, id int) error {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return err
+	}
+	p.svn.Lock()
+	defer p.svn.Unlock()
+	cs := p.svn.Prepared()
+	if cs == nil || cs.Id != id {
+		return errors.New(fmt.Sprintf(errSvnNotPrepared, id))
+	}
+	changes, err := p.svnChanges(ctx)
+	if err != nil {
+		return err
+	}
+	if !sameChanges(cs.Changes, changes) {
+		return errors.New(fmt.Sprintf(errSvnChangeSetDrifted, id))
+	}
+	added, deleted, paths := make([]string, 0), make([]string, 0), make([]string, 0, len(changes))
+	for _, v := range changes {
+		switch v.Item {
+		case SvnItemUnversioned:
+			added = append(added, v.Path)
+		case SvnItemMissing:
+			deleted = append(deleted, v.Path)
+		}
+		paths = append(paths, v.Path)
+	}
+	if len(added) > 0 {
+		if err := p.svn.AddPaths(ctx, added...); err != nil {
+			return err
+		}
+	}
+	if len(deleted) > 0 {
+		if err := p.svn.DeletePaths(ctx, deleted...); err != nil {
+			return err
+		}
+	}
+	if len(paths) > 0 {
+		if err := p.svn.CommitPaths(ctx, cs.Message, paths...); err != nil {
+			return err
+		}
+	}
+	p.svn.SetPrepared(nil)
+	appendTaskMessage(ctx, fmt.Sprintf(msgSvnConfirmed, id, len(paths)))
+	return nil
+}
+
+// SvnDiscard cleans the working copy and drops the prepared change set
+func (ph *projects) SvnDiscard(projectName string, id int) error {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return err
+	}
+	p.svn.Lock()
+	defer p.svn.Unlock()
+	if cs := p.svn.Prepared(); cs == nil || cs.Id != id {
+		return errors.New(fmt.Sprintf(errSvnNotPrepared, id))
+	}
+	// the svn was locked above, so the clean script was executed directly instead of the Clean
+	if _, err := p.svn.ExecuteWithArgs(p.ctx, cmdClean); err != nil {
+		return err
+	}
+	p.svn.SetPrepared(nil)
+	klog.Infof("the svn change set #%d of the project: %s was discarded", id, projectName)
+	return nil
+}
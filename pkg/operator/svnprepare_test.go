@@ -0,0 +1,101 @@
+package operator
+
+import (
+	"context"
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testStatusXml = `<?xml version="1.0" encoding="UTF-8"?>
+<status>
+<target path=".">
+<entry path="new.txt"><wc-status item="unversioned" props="none"></wc-status></entry>
+<entry path="a.txt"><wc-status item="modified" props="none" revision="3"></wc-status></entry>
+<entry path="dir"><wc-status item="normal" props="modified" revision="3"></wc-status></entry>
+<entry path="gone.txt"><wc-status item="missing" props="none" revision="3"></wc-status></entry>
+<entry path="ext"><wc-status item="external" props="none"></wc-status></entry>
+</target>
+</status>`
+
+func Test_svnChanges(t *testing.T) {
+	workDir, err := ioutil.TempDir("", "go-gpt-svn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(workDir)
+	if err := ioutil.WriteFile(filepath.Join(workDir, "new.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(workDir, "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rest := StatusResponse{}
+	if err := xml.Unmarshal([]byte(testStatusXml), &rest); err != nil {
+		t.Fatal(err)
+	}
+	changes := svnChanges(workDir, rest.Entries)
+	want := []SvnChange{
+		{Path: "new.txt", Item: SvnItemUnversioned, Size: 5},
+		{Path: "a.txt", Item: SvnItemModified},
+		{Path: "dir", Item: SvnItemModified, Dir: true},
+		{Path: "gone.txt", Item: SvnItemMissing},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("svnChanges() = %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("svnChanges()[%d] = %v, want %v", i, changes[i], want[i])
+		}
+	}
+	cs := &SvnChangeSet{Changes: changes}
+	cs.count()
+	if cs.Added != 1 || cs.Modified != 2 || cs.Deleted != 1 {
+		t.Errorf("count() = %d added, %d modified, %d deleted, want 1, 2, 1", cs.Added, cs.Modified, cs.Deleted)
+	}
+	if !sameChanges(changes, []SvnChange{want[3], want[2], want[1], want[0]}) {
+		t.Errorf("sameChanges() = false for the reordered changes")
+	}
+	if sameChanges(changes, []SvnChange{want[0], want[1], want[2], {Path: "gone.txt", Item: SvnItemDeleted}}) {
+		t.Errorf("sameChanges() = true for the changed item")
+	}
+}
+
+func Test_projects_SvnDiscard(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-gpt-svn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	calls := filepath.Join(dir, "calls")
+	script := filepath.Join(dir, svnScriptName)
+	// the fake script records the commands
+	if err := ioutil.WriteFile(script, []byte(`echo "$5" >> `+calls+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	s := &svn{ScriptPath: script, ctx: context.Background()}
+	s.SetPrepared(&SvnChangeSet{Id: 3, Branch: "dev"})
+	ph := &projects{projects: map[string]*project{"projectName": {svn: s, ctx: context.Background()}}}
+	if _, err := ph.AsyncTask(&Command{ProjectName: "projectName", Command: TaskCmdSvnConfirm, PrepareId: 4}); err == nil {
+		t.Error("AsyncTask() error = nil, want the confirm of the change set which was not prepared to be refused")
+	}
+	if err := ph.SvnDiscard("projectName", 4); err == nil {
+		t.Error("SvnDiscard() error = nil, want the change set #4 was not prepared")
+	}
+	if err := ph.SvnDiscard("projectName", 3); err != nil {
+		t.Fatal(err)
+	}
+	if cs := s.Prepared(); cs != nil {
+		t.Errorf("Prepared() = %v, want nil after the discard", cs)
+	}
+	out, err := ioutil.ReadFile(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != cmdClean+"\n" {
+		t.Errorf("calls = %q, want the clean only", out)
+	}
+}
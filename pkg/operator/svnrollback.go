@@ -0,0 +1,145 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog"
+)
+
+const (
+	errSvnRevisionInvalid = "the svn revision `%s` is invalid, it should be a revision or a range such as 120-125"
+	errSvnMergeConflicted = "the rollback of the svn revision `%s` was conflicted at %s"
+
+	// svnRollbackTemplate is the marker of the rollback commits, it would be parsed by the log to link the revisions
+	svnRollbackTemplate      = "[rollback %s] %s (task #%d)"
+	svnRollbackRangeTemplate = "r%d-r%d"
+	svnRollbackRevTemplate   = "r%d"
+
+	defaultSvnRollbackMessage = "rolled back"
+)
+
+var svnRollbackPattern = regexp.MustCompile(`^\[rollback r(\d+)(?:-r(\d+))?\]`)
+
+// parseRevisionRange parses a revision `123` or an inclusive range `120-125`
+func parseRevisionRange(s string) (from, to int, err error) {
+	fields := strings.SplitN(strings.TrimSpace(s), "-", 2)
+	from, err = strconv.Atoi(strings.TrimPrefix(fields[0], "r"))
+	if err != nil || from < 1 {
+		return from, to, errors.New(fmt.Sprintf(errSvnRevisionInvalid, s))
+	}
+	to = from
+	if len(fields) == 2 {
+		to, err = strconv.Atoi(strings.TrimPrefix(fields[1], "r"))
+		if err != nil || to < from {
+			return from, to, errors.New(fmt.Sprintf(errSvnRevisionInvalid, s))
+		}
+	}
+	return from, to, nil
+}
+
+// reverseMergeArgs returns the arguments of `svn merge` undoing the revisions from..to
+func reverseMergeArgs(from, to int) []string {
+	if from == to {
+		return []string{"-c", fmt.Sprintf("-%d", from)}
+	}
+	return []string{"-r", fmt.Sprintf("%d:%d", to, from-1)}
+}
+
+func rollbackMessage(from, to int, message string, taskId int) string {
+	rev := fmt.Sprintf(svnRollbackRevTemplate, from)
+	if from != to {
+		rev = fmt.Sprintf(svnRollbackRangeTemplate, from, to)
+	}
+	return fmt.Sprintf(svnRollbackTemplate, rev, message, taskId)
+}
+
+// annotateRollbacks links the rollback commits and the revisions they undid in the same log
+func annotateRollbacks(entries []Logentry) {
+	index := make(map[int]int, len(entries))
+	for i, v := range entries {
+		if r, err := strconv.Atoi(v.Revision); err == nil {
+			index[r] = i
+		}
+	}
+	for i, v := range entries {
+		m := svnRollbackPattern.FindStringSubmatch(v.Msg)
+		if m == nil {
+			continue
+		}
+		from, _ := strconv.Atoi(m[1])
+		to := from
+		if m[2] != "" {
+			to, _ = strconv.Atoi(m[2])
+		}
+		for r := from; r <= to; r++ {
+			entries[i].RollbackOf = append(entries[i].RollbackOf, strconv.Itoa(r))
+			if j, ok := index[r]; ok {
+				entries[j].RolledBackBy = v.Revision
+			}
+		}
+	}
+}
+
+// SvnRollback reverse-merges the revision or the range at the head of the svn, and commits it with the rollback marker
+func (ph *projects) SvnRollback(ctx context.Context, projectName, revision, svnMessage string) error {
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return err
+	}
+	from, to, err := parseRevisionRange(revision)
+	if err != nil {
+		return err
+	}
+	if svnMessage == "" {
+		svnMessage = defaultSvnRollbackMessage
+	}
+	p.svn.Lock()
+	defer p.svn.Unlock()
+	if cs := p.svn.Prepared(); cs != nil {
+		return errors.New(fmt.Sprintf(errSvnPrepared, cs.Id, cs.Branch))
+	}
+	if _, err := p.svn.ExecuteWithArgs(ctx, cmdUpdate); err != nil {
+		return err
+	}
+	if err := p.svn.ReverseMerge(ctx, from, to); err != nil {
+		p.cleanSvn(ctx)
+		return err
+	}
+	changes, err := p.svnChanges(ctx)
+	if err != nil {
+		p.cleanSvn(ctx)
+		return err
+	}
+	for _, v := range changes {
+		if v.Item == SvnItemConflicted {
+			p.cleanSvn(ctx)
+			return errors.New(fmt.Sprintf(errSvnMergeConflicted, revision, v.Path))
+		}
+	}
+	var taskId int
+	if t, ok := taskFromContext(ctx); ok {
+		taskId = t.Id
+	}
+	if err := p.svn.Commit(ctx, rollbackMessage(from, to, svnMessage, taskId)); err != nil {
+		p.cleanSvn(ctx)
+		return err
+	}
+	return nil
+}
+
+// cleanSvn reverts the working copy which was locked by the caller,
+// it should not be stopped by the ctx of the task which might have been timed out or cancelled, but its output would still be appended to the task
+func (p *project) cleanSvn(ctx context.Context) {
+	cleanup := p.ctx
+	if t, ok := taskFromContext(ctx); ok {
+		cleanup = withTask(cleanup, t)
+	}
+	if _, err := p.svn.ExecuteWithArgs(cleanup, cmdClean); err != nil {
+		klog.V(2).Info(err)
+	}
+}
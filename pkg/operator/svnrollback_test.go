@@ -0,0 +1,82 @@
+package operator
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_parseRevisionRange(t *testing.T) {
+	tests := []struct {
+		s        string
+		from, to int
+		wantErr  bool
+	}{
+		{s: "123", from: 123, to: 123},
+		{s: "r120-r125", from: 120, to: 125},
+		{s: "125-120", wantErr: true},
+		{s: "0", wantErr: true},
+		{s: "HEAD", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			from, to, err := parseRevisionRange(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRevisionRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (from != tt.from || to != tt.to) {
+				t.Errorf("parseRevisionRange() = %d, %d, want %d, %d", from, to, tt.from, tt.to)
+			}
+		})
+	}
+	if got := reverseMergeArgs(123, 123); !reflect.DeepEqual(got, []string{"-c", "-123"}) {
+		t.Errorf("reverseMergeArgs() = %v, want -c -123", got)
+	}
+	if got := reverseMergeArgs(120, 125); !reflect.DeepEqual(got, []string{"-r", "125:119"}) {
+		t.Errorf("reverseMergeArgs() = %v, want -r 125:119", got)
+	}
+}
+
+func Test_annotateRollbacks(t *testing.T) {
+	entries := []Logentry{
+		{Revision: "130", Msg: rollbackMessage(121, 122, "bad sync", 7) + " - committed by admin@go-gpt"},
+		{Revision: "125", Msg: rollbackMessage(110, 110, "bad sync", 6)},
+		{Revision: "122", Msg: "sync"},
+		{Revision: "121", Msg: "sync"},
+	}
+	annotateRollbacks(entries)
+	if !reflect.DeepEqual(entries[0].RollbackOf, []string{"121", "122"}) {
+		t.Errorf("RollbackOf = %v, want 121 and 122", entries[0].RollbackOf)
+	}
+	if entries[2].RolledBackBy != "130" || entries[3].RolledBackBy != "130" {
+		t.Errorf("RolledBackBy = %s, %s, want 130", entries[2].RolledBackBy, entries[3].RolledBackBy)
+	}
+	if !reflect.DeepEqual(entries[1].RollbackOf, []string{"110"}) || entries[1].RolledBackBy != "" {
+		t.Errorf("entries[1] = %v, want the rollback of 110 which was out of the log", entries[1])
+	}
+}
+
+func Test_project_cleanSvn_cancelled(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-gpt-svn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	calls := filepath.Join(dir, "calls")
+	script := filepath.Join(dir, svnScriptName)
+	if err := ioutil.WriteFile(script, []byte(`echo "$5" >> `+calls+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	p := &project{svn: &svn{ScriptPath: script, ctx: context.Background()}, ctx: context.Background()}
+	th := NewTaskHub(NewMemoryStore(), context.Background())
+	task := th.NewTask(&Command{Command: TaskCmdSvnRollback, Revision: "12"})
+	ctx, cancel := context.WithCancel(withTask(context.Background(), task))
+	cancel()
+	p.cleanSvn(ctx)
+	if out, err := ioutil.ReadFile(calls); err != nil || string(out) != cmdClean+"\n" {
+		t.Errorf("calls = %q, %v, want the clean run after the task was cancelled", out, err)
+	}
+}
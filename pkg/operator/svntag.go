@@ -0,0 +1,116 @@
+package operator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"text/template"
+
+	"k8s.io/klog"
+)
+
+// the sources of the svn tags
+const (
+	SvnTagSourceStore = "store"
+	SvnTagSourceRule  = "rule"
+)
+
+const (
+	errSvnTagRule = "the svn tag rule `%s` of the branch `%s` err:%v"
+)
+
+// SvnTagMapping is the svn tag of a branch
+type SvnTagMapping struct {
+	Branch string `json:"branch"`
+	Tag    string `json:"tag"`
+	Source string `json:"source"`
+}
+
+// ruleSvnTag returns the tag derived by the first rule which matched the branch
+func ruleSvnTag(rules []SvnTagRule, name string) (tag string, ok bool) {
+	for _, v := range rules {
+		if matched, err := path.Match(v.Pattern, name); err != nil || !matched {
+			continue
+		}
+		tpl, err := template.New("svnTag").Parse(v.Tag)
+		if err != nil {
+			klog.V(2).Info(errors.New(fmt.Sprintf(errSvnTagRule, v.Pattern, name, err)))
+			continue
+		}
+		var buf bytes.Buffer
+		data := map[string]string{
+			"Branch": name,
+			"Base":   path.Base(name),
+		}
+		if err := tpl.Execute(&buf, data); err != nil {
+			klog.V(2).Info(errors.New(fmt.Sprintf(errSvnTagRule, v.Pattern, name, err)))
+			continue
+		}
+		return buf.String(), true
+	}
+	return tag, false
+}
+
+// svnTag returns the tag which was set to the branch, or the one derived by the rules
+func (g *git) svnTag(name string) (tag, source string) {
+	if tag, ok := g.svnTags[name]; ok {
+		return tag, SvnTagSourceStore
+	}
+	if tag, ok := ruleSvnTag(g.conf.SvnTags, name); ok {
+		return tag, SvnTagSourceRule
+	}
+	return "", ""
+}
+
+func (g *git) SetSvnTag(name, tag string) (err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	t, ok := g.RemoteBranches[name]
+	if !ok {
+		return errors.New(fmt.Sprintf(errGitBranchWasNotExisted, name))
+	}
+	if err := g.store.SaveSvnTag(name, tag); err != nil {
+		return err
+	}
+	g.svnTags[name] = tag
+	t.SvnTag = tag
+	return nil
+}
+
+// DeleteSvnTag removes the tag which was set to the branch, and the branch would fall back to the rules
+func (g *git) DeleteSvnTag(name string) (err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.store.DeleteSvnTag(name); err != nil {
+		return err
+	}
+	delete(g.svnTags, name)
+	if t, ok := g.RemoteBranches[name]; ok {
+		t.SvnTag, _ = g.svnTag(name)
+	}
+	return nil
+}
+
+// SvnTags returns the tags which were set, and the ones derived by the rules for the remote branches
+func (g *git) SvnTags() []SvnTagMapping {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	res := make([]SvnTagMapping, 0)
+	for k, v := range g.svnTags {
+		res = append(res, SvnTagMapping{Branch: k, Tag: v, Source: SvnTagSourceStore})
+	}
+	for _, v := range g.ListBranches {
+		if _, ok := g.svnTags[v]; ok {
+			continue
+		}
+		if tag, ok := ruleSvnTag(g.conf.SvnTags, v); ok {
+			res = append(res, SvnTagMapping{Branch: v, Tag: tag, Source: SvnTagSourceRule})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Branch < res[j].Branch
+	})
+	return res
+}
@@ -0,0 +1,77 @@
+package operator
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_ruleSvnTag(t *testing.T) {
+	rules := []SvnTagRule{
+		{Pattern: "release/*", Tag: "tags/{{.Base}}"},
+		{Pattern: "*", Tag: "branches/{{.Branch}}"},
+	}
+	tests := []struct {
+		name   string
+		branch string
+		want   string
+		ok     bool
+	}{
+		{name: "release", branch: "release/1.2", want: "tags/1.2", ok: true},
+		{name: "fallback", branch: "master", want: "branches/master", ok: true},
+		{name: "unmatched", branch: "feature/a/b", want: "", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ruleSvnTag(rules, tt.branch)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("ruleSvnTag() = %s, %v, want %s, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func Test_git_SvnTags(t *testing.T) {
+	s := NewMemoryStore()
+	if err := s.SaveSvnTag("master", "trunk"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	g := NewGitOperator(&ProjectConfig{
+		ProjectName: "projectName",
+		Git:         GitConfig{SvnTags: []SvnTagRule{{Pattern: "release/*", Tag: "{{.Base}}"}}},
+	}, s, ctx).(*git)
+	sb := &scriptBackend{exec: func(ctx context.Context, args ...string) (res []byte, err error) {
+		if args[0] == cmdGitShowAll {
+			return []byte("* master\n  remotes/origin/master\n  remotes/origin/release/1.2\n"), nil
+		}
+		return res, nil
+	}}
+	g.mu.Lock()
+	g.backend = sb
+	g.mu.Unlock()
+	if err := g.ShowAll(true); err != nil {
+		t.Fatal(err)
+	}
+	if got := g.RemoteBranches["master"].SvnTag; got != "trunk" {
+		t.Errorf("the restored svn tag of master = %s, want trunk", got)
+	}
+	if got := g.RemoteBranches["release/1.2"].SvnTag; got != "1.2" {
+		t.Errorf("the derived svn tag of release/1.2 = %s, want 1.2", got)
+	}
+	if err := g.SetSvnTag("release/1.2", "custom"); err != nil {
+		t.Fatal(err)
+	}
+	if tags, _ := s.LoadSvnTags(); tags["release/1.2"] != "custom" {
+		t.Errorf("the svn tag was not persisted: %v", tags)
+	}
+	if err := g.DeleteSvnTag("release/1.2"); err != nil {
+		t.Fatal(err)
+	}
+	if got := g.RemoteBranches["release/1.2"].SvnTag; got != "1.2" {
+		t.Errorf("the svn tag after the delete = %s, want the derived 1.2", got)
+	}
+	if got := g.SvnTags(); len(got) != 2 || got[1].Source != SvnTagSourceRule {
+		t.Errorf("SvnTags() = %v, want master from the store and release/1.2 from the rule", got)
+	}
+}
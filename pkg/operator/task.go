@@ -1,10 +1,15 @@
 package operator
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"k8s.io/klog"
 )
 
 const (
@@ -12,50 +17,324 @@ const (
 	TaskProcessing
 	TaskCompleted
 	TaskError
+	TaskInterrupted
+	TaskCancelled
+)
+
+var taskStatusNames = map[int]string{
+	TaskWaiting:     "waiting",
+	TaskProcessing:  "processing",
+	TaskCompleted:   "completed",
+	TaskError:       "error",
+	TaskInterrupted: "interrupted",
+	TaskCancelled:   "cancelled",
+}
+
+const (
+	taskSubscriberBuffer = 1024
 )
 
+const (
+	errTaskWasNotExisted = "the task: %d is not existed"
+	errTaskWasFinished   = "the task: %d was already finished"
+	errTaskDuplicated    = "the equal command was waiting or running as the task: %d"
+
+	msgTaskCoalesced = "[%s] coalesced an equal command"
+)
+
+// taskDuplicatedError was returned when the command was rejected for the equal waiting task
+type taskDuplicatedError struct {
+	id int
+}
+
+func (e *taskDuplicatedError) Error() string {
+	return fmt.Sprintf(errTaskDuplicated, e.id)
+}
+
+func IsTaskDuplicated(err error) bool {
+	_, ok := err.(*taskDuplicatedError)
+	return ok
+}
+
 const (
 	TaskCmdGitGen    = "gitGen"
 	TaskCmdSvnCommit = "svnCommit"
+	// TaskCmdSvnPrepare synchronizes the branch into the svn working copy without committing it
+	TaskCmdSvnPrepare = "svnPrepare"
+	// TaskCmdSvnConfirm commits the change set of the Command.PrepareId which was prepared by the svnPrepare
+	TaskCmdSvnConfirm = "svnConfirm"
+	// TaskCmdSvnRollback reverse-merges the Command.Revision and commits it
+	TaskCmdSvnRollback = "svnRollback"
+	// TaskCmdSvnTag and TaskCmdSvnBranch create the server-side copies of the svn
+	TaskCmdSvnTag    = "svnTag"
+	TaskCmdSvnBranch = "svnBranch"
 	TaskCmdFtpUpload = "ftpUpload"
+	TaskCmdPipeline  = "pipeline"
+)
+
+const (
+	DedupReject   = "reject"
+	DedupCoalesce = "coalesce"
+	DedupNone     = "none"
 )
 
 // Task
 // swagger:response Task
 type Task struct {
-	mu sync.RWMutex
+	mu     sync.RWMutex
+	store  Store
+	ctx    context.Context
+	cancel context.CancelFunc
+	// subscribers receive the messages of the task in real time until it was finished
+	subscribers map[chan string]struct{}
 
-	Id      int      `json:"id"`
-	Status  int      `json:"status"`
-	Message []string `json:"message"`
-	Command Command  `json:"command"`
+	Id       int       `json:"id"`
+	Status   int       `json:"status"`
+	Message  []string  `json:"message"`
+	Command  Command   `json:"command"`
+	Attempts []Attempt `json:"attempts"`
+	// ParentId and Children link the pipeline task with the tasks of its steps
+	ParentId  int       `json:"parent_id,omitempty"`
+	Children  []int     `json:"children,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// StartedAt and FinishedAt cover all the attempts of the task
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+	Duration   time.Duration `json:"duration"`
+	Executions []Execution   `json:"executions"`
+}
+
+// Attempt records a run of the task
+type Attempt struct {
+	Number     int       `json:"number"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Error      string    `json:"error,omitempty"`
+	ErrorKind  string    `json:"error_kind,omitempty"`
+}
+
+// Execution records a script executed by the task, the secrets in the args were redacted
+type Execution struct {
+	Script     string    `json:"script"`
+	Args       []string  `json:"args"`
+	ExitCode   int       `json:"exit_code"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Output     []string  `json:"output"`
+	Error      string    `json:"error,omitempty"`
 }
 
 func (t *Task) ChangeStatus(status int) {
 	t.mu.Lock()
 	t.Status = status
-	var msg string
-	var cot = map[int]string{
-		TaskWaiting:    "waiting",
-		TaskProcessing: "processing",
-		TaskCompleted:  "completed",
-		TaskError:      "error",
+	if isTaskFinished(status) {
+		t.FinishedAt = time.Now()
+		if !t.StartedAt.IsZero() {
+			t.Duration = t.FinishedAt.Sub(t.StartedAt)
+		}
 	}
 	t.mu.Unlock()
-	msg = fmt.Sprintf("[%s] change status: %s", time.Now().Format("2006-01-02 15:04:05"), cot[status])
-	t.AppendMessage(msg)
+	t.AppendMessage(statusMessage(status))
+	if isTaskFinished(status) {
+		t.closeSubscribers()
+		// release the resources of the ctx
+		if t.cancel != nil {
+			t.cancel()
+		}
+	}
+}
+
+func statusMessage(status int) string {
+	return fmt.Sprintf("[%s] change status: %s", time.Now().Format("2006-01-02 15:04:05"), taskStatusNames[status])
+}
+
+func (t *Task) status() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.Status
 }
 
 func (t *Task) AppendMessage(msg string) {
+	t.appendOutput(msg)
+	t.save()
+}
+
+// appendOutput appends the message without persisting it, it was used for the output lines of the scripts
+// which would be persisted together with the next status changing
+func (t *Task) appendOutput(msg string) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 	t.Message = append(t.Message, msg)
+	for ch := range t.subscribers {
+		select {
+		case ch <- msg:
+		default:
+			klog.V(2).Infof("the subscriber of the task:%d was too slow, drop msg:%s", t.Id, msg)
+		}
+	}
+}
+
+// Subscribe returns the history messages and a channel which would receive the following ones,
+// the channel would be closed after the task was finished or the unsubscribe was called
+func (t *Task) Subscribe() (history []string, ch <-chan string, unsubscribe func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	history = make([]string, len(t.Message))
+	copy(history, t.Message)
+	c := make(chan string, taskSubscriberBuffer)
+	if isTaskFinished(t.Status) {
+		close(c)
+		return history, c, func() {}
+	}
+	if t.subscribers == nil {
+		t.subscribers = make(map[chan string]struct{}, 0)
+	}
+	t.subscribers[c] = struct{}{}
+	return history, c, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if _, ok := t.subscribers[c]; ok {
+			delete(t.subscribers, c)
+			close(c)
+		}
+	}
+}
+
+func (t *Task) closeSubscribers() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for ch := range t.subscribers {
+		close(ch)
+	}
+	t.subscribers = nil
+}
+
+// begin marks the task as processing and starts a new attempt, it would return false if the task had been cancelled while waiting
+func (t *Task) begin() bool {
+	t.mu.Lock()
+	if t.Status != TaskWaiting || t.ctx.Err() != nil {
+		t.mu.Unlock()
+		return false
+	}
+	now := time.Now()
+	if t.StartedAt.IsZero() {
+		t.StartedAt = now
+	}
+	t.Attempts = append(t.Attempts, Attempt{
+		Number:    len(t.Attempts) + 1,
+		StartedAt: now,
+	})
+	// the status was changed in the same critical section, so that a Cancel would not be overwritten
+	t.Status = TaskProcessing
+	t.mu.Unlock()
+	t.AppendMessage(statusMessage(TaskProcessing))
+	return true
+}
+
+// end finishes the current attempt with the err
+func (t *Task) end(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.Attempts) == 0 {
+		return
+	}
+	a := &t.Attempts[len(t.Attempts)-1]
+	a.FinishedAt = time.Now()
+	if err != nil {
+		a.Error = err.Error()
+		a.ErrorKind = errorKind(err)
+	}
+}
+
+// beginExecution records the script which was going to be executed, and returns the index of the execution
+func (t *Task) beginExecution(script string, args []string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Executions = append(t.Executions, Execution{
+		Script:    script,
+		Args:      args,
+		StartedAt: time.Now(),
+		Output:    make([]string, 0),
+	})
+	return len(t.Executions) - 1
+}
+
+// appendExecutionOutput appends the output line to both the execution and the messages of the task
+func (t *Task) appendExecutionOutput(i int, line string) {
+	t.mu.Lock()
+	t.Executions[i].Output = append(t.Executions[i].Output, line)
+	t.mu.Unlock()
+	t.appendOutput(line)
+}
+
+func (t *Task) endExecution(i int, exitCode int, err error) {
+	t.mu.Lock()
+	e := &t.Executions[i]
+	e.ExitCode = exitCode
+	e.FinishedAt = time.Now()
+	if err != nil {
+		e.Error = err.Error()
+	}
+	t.mu.Unlock()
+	t.save()
+}
+
+func (t *Task) attemptCount() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.Attempts)
+}
+
+// Cancel removes a waiting task from the queue, or kills the processes of a processing one
+func (t *Task) Cancel() error {
+	t.mu.Lock()
+	status := t.Status
+	if t.cancel == nil || isTaskFinished(status) {
+		t.mu.Unlock()
+		return errors.New(fmt.Sprintf(errTaskWasFinished, t.Id))
+	}
+	t.cancel()
+	t.mu.Unlock()
+	// the processing one would be marked as cancelled by the worker after its processes exited
+	if status == TaskWaiting {
+		t.ChangeStatus(TaskCancelled)
+	}
+	return nil
+}
+
+func (t *Task) save() {
+	if t.store == nil {
+		return
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if err := t.store.SaveTask(t); err != nil {
+		klog.V(2).Info(err)
+	}
+}
+
+func isTaskFinished(status int) bool {
+	return status != TaskWaiting && status != TaskProcessing
+}
+
+type taskContextKey struct{}
+
+// withTask binds the task to the ctx, so that the outputs of the scripts executed with the ctx would be appended to the task
+func withTask(ctx context.Context, t *Task) context.Context {
+	return context.WithValue(ctx, taskContextKey{}, t)
+}
+
+func taskFromContext(ctx context.Context) (t *Task, ok bool) {
+	t, ok = ctx.Value(taskContextKey{}).(*Task)
+	return t, ok
 }
 
 type TaskHub struct {
 	mu    sync.RWMutex
 	Max   int32
 	Tasks map[int]*Task
+	store Store
+	ctx   context.Context
 }
 
 func (th *TaskHub) getNextTaskId() int {
@@ -63,35 +342,224 @@ func (th *TaskHub) getNextTaskId() int {
 }
 
 func (th *TaskHub) NewTask(c *Command) *Task {
-	t := &Task{
-		Id:      th.getNextTaskId(),
-		Status:  TaskWaiting,
-		Message: make([]string, 0),
-		Command: *c,
+	return th.newTask(th.ctx, c, 0)
+}
+
+// NewChildTask creates the task of a pipeline step, it would be cancelled together with the ctx of the pipeline
+func (th *TaskHub) NewChildTask(ctx context.Context, parent *Task, c *Command) *Task {
+	t := th.newTask(ctx, c, parent.Id)
+	parent.mu.Lock()
+	parent.Children = append(parent.Children, t.Id)
+	parent.mu.Unlock()
+	parent.save()
+	return t
+}
+
+// NewUniqueTask returns the waiting task with the equal command if there was one, otherwise a new task.
+// The processing one would be returned as well if processing was true
+func (th *TaskHub) NewUniqueTask(c *Command, processing bool) (t *Task, created bool) {
+	th.mu.Lock()
+	for _, v := range th.Tasks {
+		if v.ParentId != 0 || v.Command != *c {
+			continue
+		}
+		if s := v.status(); s == TaskWaiting || (processing && s == TaskProcessing) {
+			th.mu.Unlock()
+			return v, false
+		}
 	}
+	t = th.buildTask(th.ctx, c, 0)
+	th.Tasks[t.Id] = t
+	th.mu.Unlock()
+	t.save()
+	return t, true
+}
+
+func (th *TaskHub) newTask(ctx context.Context, c *Command, parentId int) *Task {
+	t := th.buildTask(ctx, c, parentId)
 	th.mu.Lock()
-	defer th.mu.Unlock()
 	th.Tasks[t.Id] = t
+	th.mu.Unlock()
+	t.save()
 	return t
 }
 
+func (th *TaskHub) buildTask(ctx context.Context, c *Command, parentId int) *Task {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Task{
+		Id:         th.getNextTaskId(),
+		Status:     TaskWaiting,
+		Message:    make([]string, 0),
+		Command:    *c,
+		Attempts:   make([]Attempt, 0),
+		Executions: make([]Execution, 0),
+		ParentId:   parentId,
+		CreatedAt:  time.Now(),
+		store:      th.store,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+func (th *TaskHub) Get(id int) (t *Task, err error) {
+	th.mu.RLock()
+	defer th.mu.RUnlock()
+	if t, ok := th.Tasks[id]; ok {
+		return t, nil
+	}
+	return t, errors.New(fmt.Sprintf(errTaskWasNotExisted, id))
+}
+
+// TaskFilter filters the tasks by the fields which were not empty
+type TaskFilter struct {
+	Status     string
+	Command    string
+	BranchName string
+	Since      time.Time
+	Until      time.Time
+	Page       int
+	PageSize   int
+}
+
+// TaskPage
+// swagger:response TaskPage
+type TaskPage struct {
+	Total    int    `json:"total"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+	Tasks    []Task `json:"tasks"`
+}
+
+const (
+	defaultTaskPageSize = 20
+	maxTaskPageSize     = 200
+)
+
+func (f *TaskFilter) match(t *Task) bool {
+	if f.Status != "" && taskStatusNames[t.Status] != f.Status {
+		return false
+	}
+	if f.Command != "" && t.Command.Command != f.Command {
+		return false
+	}
+	if f.BranchName != "" && t.Command.BranchName != f.BranchName {
+		return false
+	}
+	if !f.Since.IsZero() && t.CreatedAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && t.CreatedAt.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// snapshot copies the task under the lock
+func (t *Task) snapshot() Task {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	executions := make([]Execution, 0, len(t.Executions))
+	for _, v := range t.Executions {
+		v.Args = append(make([]string, 0, len(v.Args)), v.Args...)
+		v.Output = append(make([]string, 0, len(v.Output)), v.Output...)
+		executions = append(executions, v)
+	}
+	return Task{
+		Id:         t.Id,
+		Status:     t.Status,
+		Message:    append(make([]string, 0, len(t.Message)), t.Message...),
+		Command:    t.Command,
+		Attempts:   append(make([]Attempt, 0, len(t.Attempts)), t.Attempts...),
+		ParentId:   t.ParentId,
+		Children:   append(make([]int, 0, len(t.Children)), t.Children...),
+		CreatedAt:  t.CreatedAt,
+		StartedAt:  t.StartedAt,
+		FinishedAt: t.FinishedAt,
+		Duration:   t.Duration,
+		Executions: executions,
+	}
+}
+
+// List returns the matched tasks page by page, the newest task comes first
+func (th *TaskHub) List(f TaskFilter) TaskPage {
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.PageSize < 1 {
+		f.PageSize = defaultTaskPageSize
+	}
+	if f.PageSize > maxTaskPageSize {
+		f.PageSize = maxTaskPageSize
+	}
+	th.mu.RLock()
+	matched := make([]*Task, 0)
+	for _, t := range th.Tasks {
+		t.mu.RLock()
+		ok := f.match(t)
+		t.mu.RUnlock()
+		if ok {
+			matched = append(matched, t)
+		}
+	}
+	th.mu.RUnlock()
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].Id > matched[j].Id
+	})
+	page := TaskPage{
+		Total:    len(matched),
+		Page:     f.Page,
+		PageSize: f.PageSize,
+		Tasks:    make([]Task, 0, f.PageSize),
+	}
+	for i := (f.Page - 1) * f.PageSize; i < len(matched) && i < f.Page*f.PageSize; i++ {
+		page.Tasks = append(page.Tasks, matched[i].snapshot())
+	}
+	return page
+}
+
 func (th *TaskHub) GetAll() map[int]Task {
 	th.mu.RLock()
 	defer th.mu.RUnlock()
 	res := make(map[int]Task, len(th.Tasks))
 	for i := int(th.Max); i >= 1; i-- {
 		if t, ok := th.Tasks[i]; ok {
-			t.mu.RLock()
-			res[i] = *t
-			t.mu.RUnlock()
+			res[i] = t.snapshot()
 		}
 	}
 	return res
 }
 
-func NewTaskHub() *TaskHub {
-	return &TaskHub{
+// restore loads the tasks from the store, the tasks which were unfinished at the last shutdown would be marked as interrupted
+func (th *TaskHub) restore() error {
+	max, tasks, err := th.store.LoadTasks()
+	if err != nil {
+		return err
+	}
+	th.mu.Lock()
+	defer th.mu.Unlock()
+	th.Max = int32(max)
+	for _, t := range tasks {
+		t.store = th.store
+		if t.Status == TaskWaiting || t.Status == TaskProcessing {
+			t.ChangeStatus(TaskInterrupted)
+		}
+		th.Tasks[t.Id] = t
+		if int32(t.Id) > th.Max {
+			th.Max = int32(t.Id)
+		}
+	}
+	return nil
+}
+
+func NewTaskHub(s Store, ctx context.Context) *TaskHub {
+	th := &TaskHub{
 		Max:   0,
 		Tasks: make(map[int]*Task, 0),
+		store: s,
+		ctx:   ctx,
+	}
+	if err := th.restore(); err != nil {
+		klog.V(2).Info(err)
 	}
+	return th
 }
@@ -0,0 +1,73 @@
+package operator
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTaskHub_NewUniqueTask(t *testing.T) {
+	th := NewTaskHub(NewMemoryStore(), context.Background())
+	c := &Command{ProjectName: "projectName", BranchName: "master", Command: TaskCmdGitGen}
+	first, created := th.NewUniqueTask(c, true)
+	if !created {
+		t.Fatalf("NewUniqueTask() created = false, want the first task")
+	}
+	if got, created := th.NewUniqueTask(c, true); created || got.Id != first.Id {
+		t.Errorf("NewUniqueTask() = %d, %v, want the waiting task %d", got.Id, created, first.Id)
+	}
+	other := &Command{ProjectName: "projectName", BranchName: "dev", Command: TaskCmdGitGen}
+	if _, created := th.NewUniqueTask(other, true); !created {
+		t.Errorf("NewUniqueTask() created = false for another branch")
+	}
+	first.ChangeStatus(TaskProcessing)
+	if got, created := th.NewUniqueTask(c, true); created || got.Id != first.Id {
+		t.Errorf("NewUniqueTask() = %d, %v, want the processing task %d", got.Id, created, first.Id)
+	}
+	if got, created := th.NewUniqueTask(c, false); !created || got.Id == first.Id {
+		t.Errorf("NewUniqueTask() = %d, %v, want a new task after the first one started", got.Id, created)
+	}
+}
+
+func TestTask_Cancel(t *testing.T) {
+	th := NewTaskHub(NewMemoryStore(), context.Background())
+	waiting := th.NewTask(&Command{Command: TaskCmdGitGen})
+	if err := waiting.Cancel(); err != nil {
+		t.Fatal(err)
+	}
+	if waiting.Status != TaskCancelled || waiting.begin() {
+		t.Errorf("the cancelled task = %d, want it to be skipped", waiting.Status)
+	}
+	processing := th.NewTask(&Command{Command: TaskCmdGitGen})
+	if !processing.begin() {
+		t.Fatalf("Task.begin() = false")
+	}
+	if err := processing.Cancel(); err != nil {
+		t.Fatal(err)
+	}
+	if processing.ctx.Err() != context.Canceled {
+		t.Errorf("the ctx of the processing task was not cancelled")
+	}
+	processing.ChangeStatus(TaskCancelled)
+	if err := processing.Cancel(); err == nil {
+		t.Errorf("Task.Cancel() error = nil, want the finished error")
+	}
+}
+
+func TestTaskHub_List(t *testing.T) {
+	th := NewTaskHub(NewMemoryStore(), context.Background())
+	for i := 0; i < 5; i++ {
+		th.NewTask(&Command{Command: TaskCmdGitGen, BranchName: "master"})
+	}
+	th.NewTask(&Command{Command: TaskCmdSvnCommit, BranchName: "dev"})
+	page := th.List(TaskFilter{Command: TaskCmdGitGen, Page: 2, PageSize: 2})
+	if page.Total != 5 || len(page.Tasks) != 2 || page.Tasks[0].Id != 3 {
+		t.Errorf("List() = %v, want the tasks 3 and 2 of total 5", page)
+	}
+	if page := th.List(TaskFilter{BranchName: "dev", Status: "waiting"}); page.Total != 1 || page.Tasks[0].Id != 6 {
+		t.Errorf("List() = %v, want the task 6", page)
+	}
+	if page := th.List(TaskFilter{Since: time.Now().Add(time.Hour)}); page.Total != 0 {
+		t.Errorf("List() total = %d, want 0", page.Total)
+	}
+}
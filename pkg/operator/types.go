@@ -1,5 +1,7 @@
 package operator
 
+import "time"
+
 type ProjectConfig struct {
 	ProjectName string          `yaml:"project_name"`
 	ScriptsPath string          `yaml:"scripts_path"`
@@ -7,22 +9,92 @@ type ProjectConfig struct {
 	Svn         SvnConfig       `yaml:"svn"`
 	Ftp         FtpConfig       `yaml:"ftp"`
 	Oss         AliYunOssConfig `yaml:"oss"`
+	Store       StoreConfig     `yaml:"store"`
+	// Commands are the configs of the task commands, keyed by the command name (e.g. gitGen, svnCommit, ftpUpload)
+	Commands  map[string]CommandConfig `yaml:"commands"`
+	Pipelines []PipelineConfig         `yaml:"pipelines"`
+	Schedules []ScheduleConfig         `yaml:"schedules"`
+	Retention RetentionConfig          `yaml:"retention"`
+	Changelog ChangelogConfig          `yaml:"changelog"`
+	// Workers is the number of the tasks which could be run in parallel if their resources were not conflicting, default: 1
+	Workers int           `yaml:"workers"`
+	Webhook WebhookConfig `yaml:"webhook"`
 }
 
 // git types
 type GitConfig struct {
 	WorkDir string `yaml:"work_dir"`
+	// Worktree runs each task in a temporary `git worktree` of its branch instead of checking out the WorkDir,
+	// so that the tasks of the different branches could be run in parallel
+	Worktree bool `yaml:"worktree"`
+	// WorktreeDir is the parent dir of the temporary worktrees, default: the go-gpt dir in the os.TempDir
+	WorktreeDir string `yaml:"worktree_dir"`
+	// SvnTags derive the svn tags of the branches which were not set explicitly, the first matched rule wins
+	SvnTags  []SvnTagRule   `yaml:"svn_tags"`
+	Branches BranchesConfig `yaml:"branches"`
+	// Backend runs the fetch, branch listing, checkout, log and diff by git.sh (script) or go-git (native), default: script
+	Backend string `yaml:"backend"`
+	// Poll fetches the remote branches periodically, default: every 10s
+	Poll PollConfig `yaml:"poll"`
+}
+
+// BranchesConfig filters the remote branches and declares their metadata, the patterns are the globs (e.g. release/*)
+// or the regular expressions wrapped by the slashes (e.g. /^feature-\d+$/)
+type BranchesConfig struct {
+	// Include keeps the matched branches only if it was not empty
+	Include []string `yaml:"include"`
+	// Exclude drops the matched branches after the Include
+	Exclude []string `yaml:"exclude"`
+	// Meta of the first matched pattern would be applied to the branch
+	Meta []BranchMeta `yaml:"meta"`
+}
+
+type BranchMeta struct {
+	Pattern     string `yaml:"pattern"`
+	DisplayName string `yaml:"display_name"`
+	// Protected branches would never be committed and pushed by the gitGen
+	Protected bool `yaml:"protected"`
+}
+
+// SvnTagRule derives the svn tag of the branches matched by the glob Pattern (e.g. release/*),
+// the Tag is a text/template with the fields Branch (e.g. release/1.2) and Base (e.g. 1.2)
+type SvnTagRule struct {
+	Pattern string `yaml:"pattern" json:"pattern"`
+	Tag     string `yaml:"tag" json:"tag"`
 }
 
 type GitInfo struct {
 	Name         string   `json:"name"`
 	ListBranches []Branch `json:"list_branches"`
+	// LastRefresh is the time of the last fetch of the remote branches, LastError is empty if it was succeeded
+	LastRefresh time.Time `json:"last_refresh"`
+	LastError   string    `json:"last_error"`
+	// TaskCount is the number of the git commands which were waiting or running, CurrentTask is the running one
+	TaskCount   int32  `json:"task_count"`
+	CurrentTask string `json:"current_task"`
 }
 
 type Branch struct {
-	Name   string `json:"name"`
-	Active int    `json:"active"`
-	SvnTag string `json:"svn_tag"`
+	Name        string  `json:"name"`
+	Active      int     `json:"active"`
+	SvnTag      string  `json:"svn_tag"`
+	Head        *Commit `json:"head,omitempty"`
+	DisplayName string  `json:"display_name,omitempty"`
+	Protected   bool    `json:"protected"`
+}
+
+type Commit struct {
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author"`
+	Email   string    `json:"email"`
+	Subject string    `json:"subject"`
+	Time    time.Time `json:"time"`
+}
+
+type FileChange struct {
+	Name      string `json:"name"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
 }
 
 // svn types
@@ -34,6 +106,33 @@ type SvnConfig struct {
 	Url       string `yaml:"url"`
 	Port      int    `yaml:"port"`
 	RemoteDir string `yaml:"remote_dir"`
+
+	// Poll runs the clean and the update of the WorkDir periodically, default: every 10s
+	Poll PollConfig `yaml:"poll"`
+
+	// Tags and Branches are the server-side copies of the RemoteDir created by the svnTag and svnBranch commands
+	Tags     SvnCopyConfig `yaml:"tags"`
+	Branches SvnCopyConfig `yaml:"branches"`
+}
+
+type SvnCopyConfig struct {
+	// Dir is the parent of the copies relative to the root of the svn server, default: the tags or the branches beside the RemoteDir
+	Dir string `yaml:"dir"`
+	// Template is a text/template of the name with the Date, Version (the next one of the ftp) and Branch,
+	// default: {{.Date}}{{.Version}}
+	Template string `yaml:"template"`
+}
+
+// PollConfig declares the polling of the remote, the interval was doubled for each failure in a row until MaxBackoff
+type PollConfig struct {
+	// Disabled stops the polling, e.g. the git branches were refreshed by the webhook
+	Disabled bool `yaml:"disabled"`
+	// Interval between the polls, default: 10s
+	Interval time.Duration `yaml:"interval"`
+	// MaxBackoff is the max interval while the remote was failing, default: 5m
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+	// Jitter is the max factor of the random delay added to the interval, default: 0.1
+	Jitter float64 `yaml:"jitter"`
 }
 
 // ftp types
@@ -50,12 +149,102 @@ type FtpConfig struct {
 // Command
 // swagger:response Command
 type Command struct {
-	ProjectName string `json:"project_name"`
-	BranchName  string `json:"branch_name"`
-	Command     string `json:"command"`
-	Message     string `json:"message"`
-	ZipType     string `json:"zip_type"`
-	ZipFlags    string `json:"zip_flags"`
+	ProjectName string `json:"project_name" yaml:"-"`
+	BranchName  string `json:"branch_name" yaml:"branch_name"`
+	Command     string `json:"command" yaml:"command"`
+	Message     string `json:"message" yaml:"message"`
+	ZipType     string `json:"zip_type" yaml:"zip_type"`
+	ZipFlags    string `json:"zip_flags" yaml:"zip_flags"`
+	Pipeline    string `json:"pipeline,omitempty" yaml:"pipeline"`
+	// Revision is the svn revision or the range (e.g. 120-125) rolled back by the svnRollback
+	Revision string `json:"revision,omitempty" yaml:"revision"`
+	// CopyName is the name of the svn tag or branch created by the svnTag or svnBranch, it would be rendered by the template if it was empty
+	CopyName string `json:"copy_name,omitempty" yaml:"copy_name"`
+	// PrepareId is the id of the svn change set committed by the svnConfirm
+	PrepareId int `json:"prepare_id,omitempty" yaml:"-"`
+}
+
+// CommandConfig
+type CommandConfig struct {
+	// Timeout kills the processes of the task once it was exceeded, zero means no limit
+	Timeout time.Duration `yaml:"timeout"`
+	// Retry is opt-in, the failed task would not be retried unless Retry.MaxAttempts was greater than 1
+	Retry RetryConfig `yaml:"retry"`
+	// Dedup decides what to do with the command which was equal to a waiting one (reject|coalesce|none), default: reject,
+	// the reject takes the processing one as the duplicate as well
+	Dedup string `yaml:"dedup"`
+	// Resources are the resources (git|svn|ftp) locked by the task while it was running,
+	// the defaults of the builtin commands would be used if it was empty
+	Resources []string `yaml:"resources"`
+}
+
+type RetryConfig struct {
+	// MaxAttempts is the max number of the attempts including the first one
+	MaxAttempts int `yaml:"max_attempts"`
+	// BaseDelay and MaxDelay are the exponential backoff between the attempts,
+	// workqueue.DefaultControllerRateLimiter would be used if BaseDelay was empty
+	BaseDelay time.Duration `yaml:"base_delay"`
+	MaxDelay  time.Duration `yaml:"max_delay"`
+	// RetryOn are the kinds of the errors which should be retried (network|exit|timeout|unknown), default: network
+	RetryOn []string `yaml:"retry_on"`
+}
+
+// PipelineConfig
+// swagger:response PipelineConfig
+type PipelineConfig struct {
+	Name  string         `yaml:"name" json:"name"`
+	Steps []PipelineStep `yaml:"steps" json:"steps"`
+}
+
+// PipelineStep runs one of the task commands, the pipeline stops at the failed step unless ContinueOnError was set.
+// The parameters of the step override the ones shared by the pipeline run
+type PipelineStep struct {
+	Command         string `yaml:"command" json:"command"`
+	Message         string `yaml:"message" json:"message,omitempty"`
+	ZipType         string `yaml:"zip_type" json:"zip_type,omitempty"`
+	ZipFlags        string `yaml:"zip_flags" json:"zip_flags,omitempty"`
+	ContinueOnError bool   `yaml:"continue_on_error" json:"continue_on_error"`
+}
+
+// ScheduleConfig enqueues the command at the time matched by the cron expression (e.g. "30 2 * * *")
+type ScheduleConfig struct {
+	Name    string  `yaml:"name"`
+	Cron    string  `yaml:"cron"`
+	Command Command `yaml:"command"`
+}
+
+// WebhookConfig enqueues the commands of the matched rules when a branch was pushed to the git server
+type WebhookConfig struct {
+	// Secret is the secret token of GitLab, or the HMAC key of the GitHub/Gitea signature, the webhook was disabled if it was empty
+	Secret string        `yaml:"secret"`
+	Rules  []WebhookRule `yaml:"rules"`
+}
+
+// WebhookRule enqueues the Commands with the pushed branch if it was matched by the Branch pattern
+type WebhookRule struct {
+	// Branch is a glob, or a regular expression if it was wrapped by the slashes
+	Branch   string    `yaml:"branch"`
+	Commands []Command `yaml:"commands"`
+}
+
+type ChangelogConfig struct {
+	// Template is a text/template rendering the Changelog
+	Template string `yaml:"template"`
+	// Introduce replaces the introduce file of the ftp publish with the changelog
+	Introduce bool `yaml:"introduce"`
+	// Limit is the max number of the git commits and the svn revisions, default: 100
+	Limit int `yaml:"limit"`
+}
+
+// RetentionConfig prunes the finished tasks periodically, a task would be kept if it was one of the latest KeepLast tasks
+// or younger than MaxAge, and the failed tasks would also be kept if they were younger than FailedMaxAge.
+// Nothing would be pruned if both KeepLast and MaxAge were empty
+type RetentionConfig struct {
+	KeepLast     int           `yaml:"keep_last"`
+	MaxAge       time.Duration `yaml:"max_age"`
+	FailedMaxAge time.Duration `yaml:"failed_max_age"`
+	// Interval of the pruning, default: 1h
+	Interval time.Duration `yaml:"interval"`
 }
 
 // AliYunOss types
@@ -75,6 +264,12 @@ type AliYunOssEnv struct {
 	Value string `yaml:"value"`
 }
 
+// store types
+type StoreConfig struct {
+	// Path is the file of the embedded bolt database, the tasks would be kept in memory only if it was empty
+	Path string `yaml:"path"`
+}
+
 // NoticeContent
 // swagger:response NoticeContent
 type NoticeContent struct {
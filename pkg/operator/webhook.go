@@ -0,0 +1,180 @@
+package operator
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/klog"
+)
+
+const (
+	errWebhookDisabled     = "the webhook of the project: %s was disabled"
+	errWebhookUnauthorized = "the webhook of the project: %s was unauthorized"
+	errWebhookPayload      = "the webhook payload of the project: %s is invalid err:%v"
+
+	// webhookRefreshTimeout is shorter than the gitRefreshTimeout, the git servers would not wait for the response for long
+	webhookRefreshTimeout = time.Second * 30
+)
+
+// the headers of the git servers
+const (
+	headerGitlabToken     = "X-Gitlab-Token"
+	headerGitlabEvent     = "X-Gitlab-Event"
+	headerGithubSignature = "X-Hub-Signature-256"
+	headerGithubSha1      = "X-Hub-Signature"
+	headerGithubEvent     = "X-GitHub-Event"
+	headerGiteaSignature  = "X-Gitea-Signature"
+	headerGiteaEvent      = "X-Gitea-Event"
+	headerGogsSignature   = "X-Gogs-Signature"
+	headerGogsEvent       = "X-Gogs-Event"
+
+	eventGitlabPush = "Push Hook"
+	eventPush       = "push"
+
+	refHeadsPrefix = "refs/heads/"
+	zeroCommit     = "0000000000000000000000000000000000000000"
+)
+
+// pushPayload is the common part of the push payloads of GitLab, GitHub and Gitea
+type pushPayload struct {
+	Ref     string `json:"ref"`
+	After   string `json:"after"`
+	Deleted bool   `json:"deleted"`
+}
+
+// verifyWebhook checks the secret token of GitLab, or the HMAC signature of GitHub and Gitea
+func verifyWebhook(secret string, header http.Header, body []byte) bool {
+	if token := header.Get(headerGitlabToken); token != "" {
+		return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+	}
+	if s := header.Get(headerGithubSignature); s != "" {
+		return verifySignature(sha256.New, secret, strings.TrimPrefix(s, "sha256="), body)
+	}
+	if s := header.Get(headerGithubSha1); s != "" {
+		return verifySignature(sha1.New, secret, strings.TrimPrefix(s, "sha1="), body)
+	}
+	if s := header.Get(headerGiteaSignature); s != "" {
+		return verifySignature(sha256.New, secret, s, body)
+	}
+	if s := header.Get(headerGogsSignature); s != "" {
+		return verifySignature(sha256.New, secret, s, body)
+	}
+	return false
+}
+
+func verifySignature(h func() hash.Hash, secret, signature string, body []byte) bool {
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
+
+// isPushEvent returns false if the event header was declared and it was not a push, e.g. ping or tag push
+func isPushEvent(header http.Header) bool {
+	if e := header.Get(headerGitlabEvent); e != "" {
+		return e == eventGitlabPush
+	}
+	for _, k := range []string{headerGithubEvent, headerGiteaEvent, headerGogsEvent} {
+		if e := header.Get(k); e != "" {
+			return e == eventPush
+		}
+	}
+	return true
+}
+
+// pushedBranch returns the pushed branch of the payload, ok was false if it was not a branch or the branch was deleted
+func pushedBranch(body []byte) (branch string, ok bool, err error) {
+	var p pushPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return branch, false, err
+	}
+	if !strings.HasPrefix(p.Ref, refHeadsPrefix) || p.Deleted || p.After == zeroCommit {
+		return branch, false, nil
+	}
+	return strings.TrimPrefix(p.Ref, refHeadsPrefix), true, nil
+}
+
+// webhookCommands returns the commands of all the rules matched by the branch
+func webhookCommands(projectName, branchName string, rules []WebhookRule) []Command {
+	res := make([]Command, 0)
+	for _, v := range rules {
+		bp, err := newBranchPattern(v.Branch)
+		if err != nil {
+			klog.V(2).Info(err)
+			continue
+		}
+		if !bp.Match(branchName) {
+			continue
+		}
+		for _, c := range v.Commands {
+			c.ProjectName = projectName
+			c.BranchName = branchName
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+func (ph *projects) GitWebhook(projectName string, header http.Header, body []byte) (ids []int, err error) {
+	ids = make([]int, 0)
+	p, err := ph.GetProject(projectName)
+	if err != nil {
+		return ids, err
+	}
+	if p.conf.Webhook.Secret == "" {
+		return ids, errors.New(fmt.Sprintf(errWebhookDisabled, projectName))
+	}
+	if !verifyWebhook(p.conf.Webhook.Secret, header, body) {
+		return ids, errors.New(fmt.Sprintf(errWebhookUnauthorized, projectName))
+	}
+	if !isPushEvent(header) {
+		return ids, nil
+	}
+	branchName, ok, err := pushedBranch(body)
+	if err != nil {
+		return ids, errors.New(fmt.Sprintf(errWebhookPayload, projectName, err))
+	}
+	var commands []Command
+	if ok {
+		commands = webhookCommands(projectName, branchName, p.conf.Webhook.Rules)
+	}
+	if len(commands) == 0 {
+		// the branches were refreshed at once instead of waiting for the next tick, the deleted ones would be dropped as well
+		if err := p.git.Refresh(); err != nil {
+			klog.V(2).Info(err)
+		}
+		return ids, nil
+	}
+	// the commands should see the pushed commit, and the new branch should have been listed before they were checked
+	ctx, cancel := context.WithTimeout(p.ctx, webhookRefreshTimeout)
+	defer cancel()
+	if err := p.git.RefreshSync(ctx); err != nil {
+		return ids, err
+	}
+	for _, c := range commands {
+		c := c
+		id, err := ph.AsyncTask(&c)
+		if err != nil {
+			if !IsTaskDuplicated(err) {
+				return ids, err
+			}
+			klog.V(2).Info(err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
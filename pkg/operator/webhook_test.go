@@ -0,0 +1,84 @@
+package operator
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+)
+
+func Test_verifyWebhook(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/master"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	sig := hex.EncodeToString(mac.Sum(nil))
+	tests := []struct {
+		name   string
+		header http.Header
+		want   bool
+	}{
+		{name: "gitlab", header: http.Header{headerGitlabToken: {"secret"}}, want: true},
+		{name: "gitlab wrong token", header: http.Header{headerGitlabToken: {"wrong"}}, want: false},
+		{name: "github", header: http.Header{headerGithubSignature: {"sha256=" + sig}}, want: true},
+		{name: "github wrong signature", header: http.Header{headerGithubSignature: {"sha256=00"}}, want: false},
+		{name: "gitea", header: http.Header{headerGiteaSignature: {sig}}, want: true},
+		{name: "none", header: http.Header{}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifyWebhook("secret", tt.header, body); got != tt.want {
+				t.Errorf("verifyWebhook() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_pushedBranch(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantBranch string
+		wantOk     bool
+	}{
+		{name: "branch", body: `{"ref":"refs/heads/release/1.2","after":"abc"}`, wantBranch: "release/1.2", wantOk: true},
+		{name: "tag", body: `{"ref":"refs/tags/v1.2","after":"abc"}`, wantOk: false},
+		{name: "github deleted", body: `{"ref":"refs/heads/dev","deleted":true}`, wantOk: false},
+		{name: "gitlab deleted", body: `{"ref":"refs/heads/dev","after":"` + zeroCommit + `"}`, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			branch, ok, err := pushedBranch([]byte(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if branch != tt.wantBranch || ok != tt.wantOk {
+				t.Errorf("pushedBranch() = %s, %v, want %s, %v", branch, ok, tt.wantBranch, tt.wantOk)
+			}
+		})
+	}
+	if _, _, err := pushedBranch([]byte("ref")); err == nil {
+		t.Errorf("pushedBranch() want an error of the invalid payload")
+	}
+}
+
+func Test_webhookCommands(t *testing.T) {
+	rules := []WebhookRule{
+		{Branch: "master", Commands: []Command{{Command: TaskCmdGitGen}}},
+		{Branch: "release/*", Commands: []Command{{Command: TaskCmdPipeline, Pipeline: "release"}}},
+		{Branch: "/^(master|dev)$/", Commands: []Command{{Command: TaskCmdSvnCommit, Message: "sync"}}},
+	}
+	res := webhookCommands("p", "master", rules)
+	if len(res) != 2 || res[0].Command != TaskCmdGitGen || res[1].Command != TaskCmdSvnCommit {
+		t.Fatalf("webhookCommands() = %v, want gitGen and svnCommit", res)
+	}
+	if res[1].ProjectName != "p" || res[1].BranchName != "master" || res[1].Message != "sync" {
+		t.Errorf("webhookCommands() = %v, want the project and the branch filled", res[1])
+	}
+	if res := webhookCommands("p", "release/1.2", rules); len(res) != 1 || res[0].Pipeline != "release" {
+		t.Errorf("webhookCommands() = %v, want the release pipeline", res)
+	}
+	if res := webhookCommands("p", "feature/x", rules); len(res) != 0 {
+		t.Errorf("webhookCommands() = %v, want none", res)
+	}
+}
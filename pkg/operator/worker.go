@@ -1,6 +1,7 @@
 package operator
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -14,16 +15,33 @@ type Worker interface {
 	Add(t *Task)
 }
 
+const (
+	errTaskTimeout = "the task:%d timed out after %v"
+
+	msgTaskRetry  = "retry the attempt %d/%d in %v"
+	msgTaskGiveUp = "give up after %d attempts"
+)
+
 type worker struct {
-	p         Project
-	ch        <-chan struct{}
-	workQueue workqueue.RateLimitingInterface
+	p           Project
+	conf        ProjectConfig
+	tasks       *TaskHub
+	ch          <-chan struct{}
+	rateLimiter workqueue.RateLimiter
+	workQueue   workqueue.RateLimitingInterface
+	locker      *resourceLocker
 }
 
 func (w *worker) Run() {
 	defer w.workQueue.ShutDown()
-	go wait.Until(w.runWorker, time.Second, w.ch)
-	klog.Info("Started workers")
+	workers := w.conf.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		go wait.Until(w.runWorker, time.Second, w.ch)
+	}
+	klog.Infof("Started %d workers", workers)
 	<-w.ch
 	klog.Info("Shutting down workers")
 }
@@ -51,12 +69,22 @@ func (w *worker) processNextWorkItem() bool {
 			klog.V(2).Infof("transfer failed t:%v", task)
 			return nil
 		}
+		resources := commandResources(w.conf, task.Command)
+		if !w.locker.TryLock(resources) {
+			// the task would be run after the conflicting ones released the resources
+			w.workQueue.AddAfter(task, resourceConflictDelay)
+			return nil
+		}
+		defer w.locker.Unlock(resources)
 		if err := w.syncHandler(task); err != nil {
 			klog.V(2).Info("syncHandler err:", err)
-			defer task.ChangeStatus(TaskError)
-			//w.workQueue.AddRateLimited(task)
+			task.AppendMessage(err.Error())
+			if w.retry(task, err) {
+				return fmt.Errorf("error syncing:%v err:%s, requeuing", task, err.Error())
+			}
+			task.ChangeStatus(TaskError)
 			w.workQueue.Forget(obj)
-			return fmt.Errorf("error syncing:%v err:%s, requeuing", task, err.Error())
+			return fmt.Errorf("error syncing:%v err:%s", task, err.Error())
 		}
 		w.workQueue.Forget(obj)
 		klog.Infof("Successfully synced '%v'", task)
@@ -66,25 +94,97 @@ func (w *worker) processNextWorkItem() bool {
 }
 
 func (w *worker) syncHandler(t *Task) error {
-	t.ChangeStatus(TaskProcessing)
-	defer t.ChangeStatus(TaskCompleted)
+	if !t.begin() {
+		klog.Infof("skip the cancelled task:%d", t.Id)
+		return nil
+	}
+	ctx := withTask(t.ctx, t)
+	timeout := w.conf.Commands[t.Command.Command].Timeout
+	if timeout > 0 {
+		// the processes of the task would be killed by execute, and its ftp connections closed, once the deadline was exceeded
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	err := w.handle(ctx, t)
+	if timeout > 0 && ctx.Err() == context.DeadlineExceeded {
+		err = newTaskError(ErrorKindTimeout, fmt.Sprintf(errTaskTimeout, t.Id, timeout))
+	}
+	t.end(err)
+	if t.ctx.Err() == context.Canceled {
+		t.ChangeStatus(TaskCancelled)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	t.ChangeStatus(TaskCompleted)
+	return nil
+}
+
+// retry requeues the failed task with the rate limiter if the retry policy of its command allowed
+func (w *worker) retry(t *Task, err error) bool {
+	delay, ok := w.nextAttempt(t, err)
+	if !ok {
+		return false
+	}
+	// the same as workQueue.AddRateLimited, but the delay has been recorded in the task
+	w.workQueue.AddAfter(t, delay)
+	return true
+}
+
+// nextAttempt returns the delay of the next attempt and marks the task as waiting if it could be retried
+func (w *worker) nextAttempt(t *Task, err error) (delay time.Duration, ok bool) {
+	policy := w.conf.Commands[t.Command.Command].Retry
+	if !policy.Retryable(err) {
+		return delay, false
+	}
+	attempts := t.attemptCount()
+	if attempts >= policy.MaxAttempts {
+		t.AppendMessage(fmt.Sprintf(msgTaskGiveUp, attempts))
+		return delay, false
+	}
+	delay = w.rateLimiter.When(t)
+	t.AppendMessage(fmt.Sprintf(msgTaskRetry, attempts+1, policy.MaxAttempts, delay))
+	t.ChangeStatus(TaskWaiting)
+	return delay, true
+}
+
+func (w *worker) handle(ctx context.Context, t *Task) error {
 	c := t.Command
 	switch c.Command {
 	case TaskCmdGitGen:
-		return w.p.GitGenerate(c.ProjectName, c.BranchName)
+		return w.p.GitGenerate(ctx, c.ProjectName, c.BranchName)
 	case TaskCmdSvnCommit:
-		return w.p.SvnCommit(c.ProjectName, c.BranchName, c.Message)
+		return w.p.SvnCommit(ctx, c.ProjectName, c.BranchName, c.Message)
+	case TaskCmdSvnPrepare:
+		return w.p.SvnPrepare(ctx, c.ProjectName, c.BranchName, c.Message)
+	case TaskCmdSvnConfirm:
+		return w.p.SvnConfirm(ctx, c.ProjectName, c.PrepareId)
+	case TaskCmdSvnRollback:
+		return w.p.SvnRollback(ctx, c.ProjectName, c.Revision, c.Message)
+	case TaskCmdSvnTag:
+		return w.p.SvnCopy(ctx, c.ProjectName, SvnCopyTag, c.BranchName, c.CopyName, c.Message)
+	case TaskCmdSvnBranch:
+		return w.p.SvnCopy(ctx, c.ProjectName, SvnCopyBranch, c.BranchName, c.CopyName, c.Message)
 	case TaskCmdFtpUpload:
-		return w.p.FtpCompress(c.ProjectName, c.BranchName, c.ZipType, c.ZipFlags)
+		return w.p.FtpCompress(ctx, c.ProjectName, c.BranchName, c.ZipType, c.ZipFlags)
+	case TaskCmdPipeline:
+		return w.runPipeline(ctx, t)
 	}
 	return nil
 }
 
-func NewWorker(ch <-chan struct{}, p Project) Worker {
+func NewWorker(ch <-chan struct{}, p Project, conf ProjectConfig, tasks *TaskHub) Worker {
+	rateLimiter := newCommandRateLimiter(conf.Commands)
 	var w Worker = &worker{
-		p:         p,
-		ch:        ch,
-		workQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "workQueue"),
+		p:           p,
+		conf:        conf,
+		tasks:       tasks,
+		ch:          ch,
+		rateLimiter: rateLimiter,
+		workQueue:   workqueue.NewNamedRateLimitingQueue(rateLimiter, "workQueue"),
+		locker:      newResourceLocker(),
 	}
 	go w.Run()
 	return w
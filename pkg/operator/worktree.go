@@ -0,0 +1,95 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s.io/klog"
+)
+
+const (
+	defaultWorktreeDir     = "go-gpt"
+	worktreeBranchTemplate = "gpt-%d"
+)
+
+type workDirContextKey struct{}
+
+// withWorkDir binds the worktree to the ctx, so that the git scripts executed with the ctx would be run in it
+func withWorkDir(ctx context.Context, dir string) context.Context {
+	return context.WithValue(ctx, workDirContextKey{}, dir)
+}
+
+func workDirFromContext(ctx context.Context) (dir string, ok bool) {
+	dir, ok = ctx.Value(workDirContextKey{}).(string)
+	return dir, ok
+}
+
+// WorkDir returns the worktree bound to the ctx, or the shared WorkDir
+func (g *git) WorkDir(ctx context.Context) string {
+	if dir, ok := workDirFromContext(ctx); ok {
+		return dir
+	}
+	return g.conf.WorkDir
+}
+
+// worktree returns the dir and the local branch of the worktree, they were named after the task bound to the ctx
+func (g *git) worktree(ctx context.Context, name string) (dir, branch string) {
+	id := int(time.Now().UnixNano())
+	if t, ok := taskFromContext(ctx); ok {
+		id = t.Id
+	}
+	base := g.conf.WorktreeDir
+	if base == "" {
+		base = filepath.Join(os.TempDir(), defaultWorktreeDir)
+	}
+	branch = fmt.Sprintf(worktreeBranchTemplate, id)
+	dir = filepath.Join(base, g.Name, fmt.Sprintf("%s-%s", strings.Replace(name, "/", "_", -1), branch))
+	return dir, branch
+}
+
+// Checkout prepares the branch for a task, and the release must be called after the task.
+// It checks out the shared WorkDir and reverts it at the release by default,
+// or adds a temporary worktree of the branch bound to the returned ctx and removes it at the release if GitConfig.Worktree was enabled
+func (g *git) Checkout(ctx context.Context, name string) (wctx context.Context, release func(), err error) {
+	// the cleanup should not be stopped by the ctx of the task, but its output would still be appended to the task
+	cleanup := g.ctx
+	if t, ok := taskFromContext(ctx); ok {
+		cleanup = withTask(cleanup, t)
+	}
+	if !g.conf.Worktree {
+		g.workDirMu.Lock()
+		release = func() {
+			defer g.workDirMu.Unlock()
+			if err := g.revert(cleanup); err != nil {
+				klog.V(2).Info(err)
+			}
+		}
+		if err = g.CheckOutBranch(ctx, name); err != nil {
+			release()
+			return ctx, nil, err
+		}
+		return ctx, release, nil
+	}
+	if _, ok := g.branch(name); !ok {
+		return ctx, nil, errors.New(fmt.Sprintf(errGitBranchWasNotExisted, name))
+	}
+	dir, branch := g.worktree(ctx, name)
+	if err = os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return ctx, nil, err
+	}
+	release = func() {
+		if _, err := g.ExecuteWithArgs(cleanup, cmdGitWorktreeRemove, dir, branch); err != nil {
+			klog.V(2).Info(err)
+		}
+	}
+	if _, err = g.ExecuteWithArgs(ctx, cmdGitWorktreeAdd, dir, branch, g.GetBranchFullName(name)); err != nil {
+		release()
+		return ctx, nil, err
+	}
+	return withWorkDir(ctx, dir), release, nil
+}
@@ -0,0 +1,100 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_git_worktree(t *testing.T) {
+	g := &git{
+		Name: "projectName",
+		conf: GitConfig{WorkDir: "/work", Worktree: true, WorktreeDir: "/tmp/worktrees"},
+		ctx:  context.Background(),
+	}
+	th := NewTaskHub(NewMemoryStore(), context.Background())
+	task := th.NewTask(&Command{Command: TaskCmdGitGen, BranchName: "feature/a"})
+	dir, branch := g.worktree(withTask(context.Background(), task), "feature/a")
+	if want := filepath.Join("/tmp/worktrees", "projectName", "feature_a-gpt-1"); dir != want || branch != "gpt-1" {
+		t.Errorf("worktree() = %s, %s, want %s, gpt-1", dir, branch, want)
+	}
+	if got := g.WorkDir(context.Background()); got != "/work" {
+		t.Errorf("WorkDir() = %s, want the shared WorkDir", got)
+	}
+	if got := g.WorkDir(withWorkDir(context.Background(), dir)); got != dir {
+		t.Errorf("WorkDir() = %s, want the worktree %s", got, dir)
+	}
+}
+
+func Test_git_Common_worktree(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-gpt-git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	script := filepath.Join(dir, gitScriptName)
+	// the fake script records the work dir of each command
+	if err := ioutil.WriteFile(script, []byte(`echo "$2 $1" >> `+filepath.Join(dir, "calls")+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	g := &git{
+		Name:           "projectName",
+		ScriptPath:     script,
+		conf:           GitConfig{WorkDir: "/work", Worktree: true, WorktreeDir: filepath.Join(dir, "worktrees")},
+		RemoteBranches: map[string]*Branch{"dev": {Name: "dev"}},
+		ctx:            context.Background(),
+	}
+	th := NewTaskHub(NewMemoryStore(), context.Background())
+	ctx := withTask(context.Background(), th.NewTask(&Command{Command: TaskCmdGitGen, BranchName: "dev"}))
+	if err := g.Common(ctx, "dev"); err != nil {
+		t.Fatal(err)
+	}
+	out, err := ioutil.ReadFile(filepath.Join(dir, "calls"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktree, _ := g.worktree(ctx, "dev")
+	if !strings.Contains(string(out), fmt.Sprintf("%s %s\n", cmdGitUpdate, worktree)) {
+		t.Errorf("calls = %s, want the update run in the worktree %s", out, worktree)
+	}
+}
+
+func Test_git_Checkout_refresh(t *testing.T) {
+	g := NewGitOperator(&ProjectConfig{ProjectName: "projectName"}, NewMemoryStore(), context.Background()).(*git)
+	g.backend = &scriptBackend{exec: func(ctx context.Context, args ...string) (res []byte, err error) {
+		if args[0] == cmdGitShowAll {
+			return []byte("* master\n  remotes/origin/master\n"), nil
+		}
+		return res, nil
+	}}
+	if err := g.ShowAll(true); err != nil {
+		t.Fatal(err)
+	}
+	_, release, err := g.Checkout(context.Background(), "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- g.HandleCommand(&GitCmd{cmd: cmdGitUpdate})
+	}()
+	select {
+	case <-done:
+		t.Fatal("HandleCommand() refreshed the shared WorkDir while it was checked out")
+	case <-time.After(time.Millisecond * 100):
+	}
+	release()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("HandleCommand() was not run after the release")
+	}
+}
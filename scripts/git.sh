@@ -9,6 +9,18 @@ function showAll() {
     git branch -a | grep -v HEAD
 }
 
+function heads() {
+    git for-each-ref --format='%(refname:lstrip=3)%1f%(objectname)%1f%(authorname)%1f%(authoremail)%1f%(authordate:unix)%1f%(subject)' refs/remotes/origin | grep -v '^HEAD'
+}
+
+function gitLog() {
+    ref="$1"
+    if git rev-parse --verify -q "remotes/origin/$1" > /dev/null; then
+        ref="remotes/origin/$1"
+    fi
+    git log -n "$2" --format='%H%x1f%an%x1f%ae%x1f%at%x1f%s' --end-of-options "$ref"
+}
+
 function revert() {
     git add --all
     git checkout -f
@@ -56,8 +68,27 @@ function compress() {
     exit 0
 }
 
+function pushHead() {
+    git pull
+    git push origin HEAD:"$1"
+    exit 0
+}
+
+function worktreeAdd() {
+    rm -rf "$1"
+    git worktree prune
+    git worktree add -f --track -B "$2" "$1" "$3"
+}
+
+function worktreeRemove() {
+    git worktree remove -f "$1"
+    git worktree prune
+    git branch -D "$2"
+    exit 0
+}
+
 function error() {
-    echo "Usage: git.sh {git-path} {fetch|revert|showAll|checkout|generate|commit|push|update|svnSync|compress} {name}"
+    echo "Usage: git.sh {git-path} {fetch|revert|showAll|heads|log|checkout|generate|commit|push|pushHead|update|svnSync|compress|worktreeAdd|worktreeRemove} {name}"
     exit
 }
 
@@ -75,6 +106,15 @@ case "$2" in
     "showAll")
         showAll
         ;;
+    "heads")
+        heads
+        ;;
+    "log")
+        if [[ -z "$3" ]] || [[ -z "$4" ]]; then
+            error
+        fi
+        gitLog "$3" "$4"
+        ;;
     "checkout")
         if [[ -z "$3" ]] || [[ -z "$4" ]]; then
             error
@@ -90,6 +130,12 @@ case "$2" in
     "push")
         push
         ;;
+    "pushHead")
+        if [[ -z "$3" ]]; then
+            error
+        fi
+        pushHead "$3"
+        ;;
     "update")
         update
         ;;
@@ -105,6 +151,18 @@ case "$2" in
         fi
         compress "$3" "$4" "$5"
         ;;
+    "worktreeAdd")
+        if [[ -z "$3" ]] || [[ -z "$4" ]] || [[ -z "$5" ]]; then
+            error
+        fi
+        worktreeAdd "$3" "$4" "$5"
+        ;;
+    "worktreeRemove")
+        if [[ -z "$3" ]] || [[ -z "$4" ]]; then
+            error
+        fi
+        worktreeRemove "$3" "$4"
+        ;;
     *)
         error
         ;;
@@ -38,6 +38,32 @@ function commit() {
     svn --username $1 --password $2 commit --message "${3} - committed by ${1}@go-gpt"
 }
 
+function statusXml() {
+    svn --username $1 --password $2 status --xml
+}
+
+function addPaths() {
+    local username=$1 password=$2
+    shift 2
+    svn --username $username --password $password add --parents -- "$@"
+}
+
+function deletePaths() {
+    local username=$1 password=$2
+    shift 2
+    svn --username $username --password $password delete --force -- "$@"
+}
+
+function commitPaths() {
+    local username=$1 password=$2 message=$3
+    shift 3
+    svn --username $username --password $password commit --message "${message} - committed by ${username}@go-gpt" -- "$@"
+}
+
+function merge() {
+    svn --username $1 --password $2 merge --non-interactive $3 $4 .
+}
+
 function update() {
     clean $1 $2
     svn --username $1 --password $2 update
@@ -51,6 +77,24 @@ function log() {
     svn --username $1 --password $2 log -l $3 -v --xml
 }
 
+function logRange() {
+    local username=$1 password=$2 limit=$3 revision=$4
+    shift 4
+    svn --username $username --password $password log -v --xml -l $limit -r $revision -- "$@"
+}
+
+function copy() {
+    if svn --username $1 --password $2 info "$4" > /dev/null 2>&1; then
+        echo "$4 is existed" >&2
+        return 1
+    fi
+    svn --username $1 --password $2 copy --parents --message "${5} - committed by ${1}@go-gpt" "$3" "$4"
+}
+
+function list() {
+    svn --username $1 --password $2 list --xml "$3"
+}
+
 function lock() {
     svn --username $1 --password $2 lock
 }
@@ -67,7 +111,7 @@ Usage: $(basename "$0") <username> <password> <svn-path> <svn_dir> <command> ...
   <password>          the svn account's password
   <svn-path>          the svn remote url
   <svn_dir>           the directory of local
-  <command>           the commands (e.g. checkout|addAll||status|revertAll|removeAll|clean|commit|update)
+  <command>           the commands (e.g. checkout|addAll||status|revertAll|removeAll|clean|commit|update|statusXml|addPaths|deletePaths|commitPaths|merge|logRange|info|copy|list)
   <params>            the external param for commands
 
 Examples:
@@ -113,6 +157,42 @@ case "$5" in
         cd $4
         update $1 $2
         ;;
+    "info")
+        cd $4
+        info $1 $2
+        ;;
+    "statusXml")
+        cd $4
+        statusXml $1 $2
+        ;;
+    "addPaths")
+        if [[ -z "$6" ]]; then
+            error
+        fi
+        cd $4
+        addPaths $1 $2 "${@:6}"
+        ;;
+    "deletePaths")
+        if [[ -z "$6" ]]; then
+            error
+        fi
+        cd $4
+        deletePaths $1 $2 "${@:6}"
+        ;;
+    "commitPaths")
+        if [[ -z "$6" ]] || [[ -z "$7" ]]; then
+            error
+        fi
+        cd $4
+        commitPaths $1 $2 "$6" "${@:7}"
+        ;;
+    "merge")
+        if [[ -z "$6" ]] || [[ -z "$7" ]]; then
+            error
+        fi
+        cd $4
+        merge $1 $2 "$6" "$7"
+        ;;
     "log")
         if [[ -z "$6" ]]; then
             error
@@ -120,6 +200,25 @@ case "$5" in
         cd $4
         log $1 $2 $6
         ;;
+    "logRange")
+        if [[ -z "$6" ]] || [[ -z "$7" ]]; then
+            error
+        fi
+        cd $4
+        logRange $1 $2 $6 $7 "${@:8}"
+        ;;
+    "copy")
+        if [[ -z "$6" ]] || [[ -z "$7" ]] || [[ -z "$8" ]]; then
+            error
+        fi
+        copy $1 $2 "$6" "$7" "$8"
+        ;;
+    "list")
+        if [[ -z "$6" ]]; then
+            error
+        fi
+        list $1 $2 "$6"
+        ;;
     *)
         error
         ;;
@@ -95,6 +95,78 @@ func InitHttpServer(c *conf.Config, writer io.Writer, ctx context.Context) *Http
 		}
 		c.JSON(http.StatusOK, res)
 	})
+	router.GET(RouteGitSvnTagList, func(c *gin.Context) {
+		p := &GitSvnTagListParam{
+			ProjectName: c.Param("projectName"),
+		}
+		res, err := h.router.GitSvnTagList(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.POST(RouteGitSvnTagSet, func(c *gin.Context) {
+		p := &GitSvnTagParam{
+			ProjectName: c.PostForm("projectName"),
+			BranchName:  c.PostForm("branchName"),
+			SvnTag:      c.PostForm("svnTag"),
+		}
+		res, err := h.router.GitSvnTagSet(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.POST(RouteGitSvnTagDelete, func(c *gin.Context) {
+		p := &GitSvnTagParam{
+			ProjectName: c.PostForm("projectName"),
+			BranchName:  c.PostForm("branchName"),
+		}
+		res, err := h.router.GitSvnTagDelete(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.POST(RouteGitRefresh, func(c *gin.Context) {
+		p := &GitRefreshParam{
+			ProjectName: c.Param("projectName"),
+		}
+		res, err := h.router.GitRefresh(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.POST(RouteGitWebhook, func(c *gin.Context) {
+		p := &GitWebhookParam{
+			ProjectName: c.Param("projectName"),
+			Header:      c.Request.Header,
+		}
+		body, err := c.GetRawData()
+		if err != nil {
+			c.JSON(http.StatusOK, GetQuickErrorResponse(CodeUnknownError))
+			return
+		}
+		p.Body = body
+		res, err := h.router.GitWebhook(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.GET(RouteGitLog, func(c *gin.Context) {
+		p := &GitLogParam{
+			ProjectName: c.Param("projectName"),
+			BranchName:  c.Param("branchName"),
+		}
+		p.Limit, _ = strconv.Atoi(c.Query("limit"))
+		res, err := h.router.GitLog(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
 	router.GET(RouteSvnCommit, func(c *gin.Context) {
 		p := &SvnCommitParam{
 			ProjectName: c.Param("projectName"),
@@ -107,6 +179,126 @@ func InitHttpServer(c *conf.Config, writer io.Writer, ctx context.Context) *Http
 		}
 		c.JSON(http.StatusOK, res)
 	})
+	router.POST(RouteSvnPrepare, func(c *gin.Context) {
+		p := &SvnPrepareParam{
+			ProjectName: c.PostForm("projectName"),
+			BranchName:  c.PostForm("branchName"),
+			SvnMessage:  c.PostForm("svnMsg"),
+		}
+		res, err := h.router.SvnPrepare(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.GET(RouteSvnPrepared, func(c *gin.Context) {
+		p := &SvnPreparedParam{
+			ProjectName: c.Param("projectName"),
+		}
+		res, err := h.router.SvnPrepared(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.POST(RouteSvnConfirm, func(c *gin.Context) {
+		id, err := strconv.Atoi(c.PostForm("prepareId"))
+		if err != nil {
+			c.JSON(http.StatusOK, GetQuickErrorResponse(CodeUnknownError))
+			return
+		}
+		p := &SvnChangeSetParam{
+			ProjectName: c.PostForm("projectName"),
+			PrepareId:   id,
+		}
+		res, err := h.router.SvnConfirm(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.POST(RouteSvnDiscard, func(c *gin.Context) {
+		id, err := strconv.Atoi(c.PostForm("prepareId"))
+		if err != nil {
+			c.JSON(http.StatusOK, GetQuickErrorResponse(CodeUnknownError))
+			return
+		}
+		p := &SvnChangeSetParam{
+			ProjectName: c.PostForm("projectName"),
+			PrepareId:   id,
+		}
+		res, err := h.router.SvnDiscard(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.POST(RouteSvnRollback, func(c *gin.Context) {
+		p := &SvnRollbackParam{
+			ProjectName: c.PostForm("projectName"),
+			Revision:    c.PostForm("revision"),
+			SvnMessage:  c.PostForm("svnMsg"),
+		}
+		res, err := h.router.SvnRollback(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.GET(RouteSvnLogList, func(c *gin.Context) {
+		p := &SvnLogListParam{
+			ProjectName: c.Param("projectName"),
+			Author:      c.Query("author"),
+			Since:       c.Query("since"),
+			Until:       c.Query("until"),
+			Message:     c.Query("message"),
+			Path:        c.Query("path"),
+		}
+		p.From, _ = strconv.Atoi(c.Query("from"))
+		p.To, _ = strconv.Atoi(c.Query("to"))
+		p.Before, _ = strconv.Atoi(c.Query("before"))
+		p.Limit, _ = strconv.Atoi(c.Query("limit"))
+		res, err := h.router.SvnLogList(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.GET(RouteSvnInfo, func(c *gin.Context) {
+		p := &SvnInfoParam{
+			ProjectName: c.Param("projectName"),
+		}
+		res, err := h.router.SvnInfo(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.POST(RouteSvnCopyCreate, func(c *gin.Context) {
+		p := &SvnCopyCreateParam{
+			ProjectName: c.PostForm("projectName"),
+			Kind:        c.PostForm("kind"),
+			Name:        c.PostForm("name"),
+			BranchName:  c.PostForm("branchName"),
+			SvnMessage:  c.PostForm("svnMsg"),
+		}
+		res, err := h.router.SvnCopyCreate(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.GET(RouteSvnCopyList, func(c *gin.Context) {
+		p := &SvnCopyListParam{
+			ProjectName: c.Param("projectName"),
+			Kind:        c.Param("kind"),
+		}
+		res, err := h.router.SvnCopyList(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
 	router.GET(RouteSvnLog, func(c *gin.Context) {
 		i, err := strconv.Atoi(c.Param("logNumber"))
 		if err != nil {
@@ -170,6 +362,17 @@ func InitHttpServer(c *conf.Config, writer io.Writer, ctx context.Context) *Http
 		}
 		c.JSON(http.StatusOK, res)
 	})
+	router.GET(RouteReleaseChangelog, func(c *gin.Context) {
+		p := &ReleaseChangelogParam{
+			ProjectName: c.Param("projectName"),
+			BranchName:  c.Param("branchName"),
+		}
+		res, err := h.router.ReleaseChangelog(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
 	router.GET(RouteTaskAll, func(c *gin.Context) {
 		p := &TaskAllParam{
 			ProjectName: c.Param("projectName"),
@@ -180,6 +383,156 @@ func InitHttpServer(c *conf.Config, writer io.Writer, ctx context.Context) *Http
 		}
 		c.JSON(http.StatusOK, res)
 	})
+	router.GET(RouteTaskList, func(c *gin.Context) {
+		p := &TaskListParam{
+			ProjectName: c.Param("projectName"),
+			Status:      c.Query("status"),
+			Command:     c.Query("command"),
+			BranchName:  c.Query("branch"),
+			Since:       c.Query("since"),
+			Until:       c.Query("until"),
+		}
+		p.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+		p.PageSize, _ = strconv.Atoi(c.Query("page_size"))
+		res, err := h.router.TaskList(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.GET(RouteTaskGet, func(c *gin.Context) {
+		i, err := strconv.Atoi(c.Param("taskId"))
+		if err != nil {
+			c.JSON(http.StatusOK, GetQuickErrorResponse(CodeUnknownError))
+			return
+		}
+		p := &TaskGetParam{
+			ProjectName: c.Param("projectName"),
+			TaskId:      i,
+		}
+		res, err := h.router.TaskGet(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.POST(RouteTaskCancel, func(c *gin.Context) {
+		i, err := strconv.Atoi(c.Param("taskId"))
+		if err != nil {
+			c.JSON(http.StatusOK, GetQuickErrorResponse(CodeUnknownError))
+			return
+		}
+		p := &TaskCancelParam{
+			ProjectName: c.Param("projectName"),
+			TaskId:      i,
+		}
+		res, err := h.router.TaskCancel(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.GET(RouteTaskStream, func(c *gin.Context) {
+		i, err := strconv.Atoi(c.Param("taskId"))
+		if err != nil {
+			c.JSON(http.StatusOK, GetQuickErrorResponse(CodeUnknownError))
+			return
+		}
+		p := &TaskStreamParam{
+			ProjectName: c.Param("projectName"),
+			TaskId:      i,
+		}
+		history, ch, unsubscribe, err := h.router.TaskStream(p)
+		if err != nil {
+			c.JSON(http.StatusOK, GetQuickErrorResponse(CodeUnknownError))
+			return
+		}
+		defer unsubscribe()
+		for _, v := range history {
+			c.SSEvent("message", v)
+		}
+		c.Stream(func(w io.Writer) bool {
+			select {
+			case msg, ok := <-ch:
+				if !ok {
+					c.SSEvent("end", "")
+					return false
+				}
+				c.SSEvent("message", msg)
+				return true
+			case <-c.Request.Context().Done():
+				return false
+			}
+		})
+	})
+	router.GET(RoutePipelineList, func(c *gin.Context) {
+		p := &PipelineListParam{
+			ProjectName: c.Param("projectName"),
+		}
+		res, err := h.router.PipelineList(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.POST(RoutePipelineRun, func(c *gin.Context) {
+		p := &PipelineRunParam{
+			ProjectName:  c.PostForm("projectName"),
+			PipelineName: c.PostForm("pipelineName"),
+			BranchName:   c.PostForm("branchName"),
+			Message:      c.PostForm("message"),
+			ZipType:      c.PostForm("zipType"),
+			ZipFlags:     c.PostForm("zipFlags"),
+		}
+		res, err := h.router.PipelineRun(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.GET(RouteScheduleList, func(c *gin.Context) {
+		p := &ScheduleListParam{
+			ProjectName: c.Param("projectName"),
+		}
+		res, err := h.router.ScheduleList(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.POST(RouteSchedulePause, func(c *gin.Context) {
+		p := &ScheduleParam{
+			ProjectName:  c.Param("projectName"),
+			ScheduleName: c.Param("scheduleName"),
+		}
+		res, err := h.router.SchedulePause(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.POST(RouteScheduleResume, func(c *gin.Context) {
+		p := &ScheduleParam{
+			ProjectName:  c.Param("projectName"),
+			ScheduleName: c.Param("scheduleName"),
+		}
+		res, err := h.router.ScheduleResume(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	router.POST(RouteScheduleTrigger, func(c *gin.Context) {
+		p := &ScheduleParam{
+			ProjectName:  c.Param("projectName"),
+			ScheduleName: c.Param("scheduleName"),
+		}
+		res, err := h.router.ScheduleTrigger(p)
+		if err != nil {
+			res = GetQuickErrorResponse(CodeUnknownError)
+		}
+		c.JSON(http.StatusOK, res)
+	})
 	router.GET(RouteOssEnvs, func(c *gin.Context) {
 		p := &OssEnvsParam{
 			ProjectName: c.Param("projectName"),
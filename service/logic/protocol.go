@@ -1,8 +1,11 @@
 package logic
 
+import "github.com/Shanghai-Lunara/go-gpt/pkg/operator"
+
 const (
 	CodeSuccess = 10000 + iota
 	CodeUnknownError
+	CodeTaskDuplicated
 )
 
 type HttpRequest struct {
@@ -37,3 +40,15 @@ func GetQuickErrorResponse(code int) HttpResponse {
 		Data:    map[string]interface{}{},
 	}
 }
+
+// GetAsyncTaskResponse responds the id of the enqueued task,
+// or the id of the waiting one if the command was rejected as a duplicate
+func GetAsyncTaskResponse(id int, err error) (HttpResponse, error) {
+	if err != nil {
+		if operator.IsTaskDuplicated(err) {
+			return GetResponse(CodeTaskDuplicated, err.Error(), map[string]interface{}{"task_id": id}), nil
+		}
+		return HttpResponse{}, err
+	}
+	return GetQuickResponse(map[string]interface{}{"task_id": id}), nil
+}
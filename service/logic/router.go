@@ -10,21 +10,52 @@
 package logic
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
 	"github.com/Shanghai-Lunara/go-gpt/pkg/operator"
 	"k8s.io/klog"
 )
 
 type Router interface {
 	GetGitAll() (res HttpResponse, err error)
+	GitRefresh(param *GitRefreshParam) (res HttpResponse, err error)
 	GitGenerate(param *GitGenerateParam) (res HttpResponse, err error) // async
 	SetGitBranchSvnTag(param *SetGitBranchSvnTagParam) (res HttpResponse, err error)
-	SvnCommit(param *SvnCommitParam) (res HttpResponse, err error) // async
+	GitLog(param *GitLogParam) (res HttpResponse, err error)
+	GitSvnTagList(param *GitSvnTagListParam) (res HttpResponse, err error)
+	GitSvnTagSet(param *GitSvnTagParam) (res HttpResponse, err error)
+	GitSvnTagDelete(param *GitSvnTagParam) (res HttpResponse, err error)
+	GitWebhook(param *GitWebhookParam) (res HttpResponse, err error) // async
+	SvnCommit(param *SvnCommitParam) (res HttpResponse, err error)   // async
+	SvnPrepare(param *SvnPrepareParam) (res HttpResponse, err error) // async
+	SvnPrepared(param *SvnPreparedParam) (res HttpResponse, err error)
+	SvnConfirm(param *SvnChangeSetParam) (res HttpResponse, err error)
+	SvnDiscard(param *SvnChangeSetParam) (res HttpResponse, err error)
+	SvnRollback(param *SvnRollbackParam) (res HttpResponse, err error) // async
 	SvnLog(param *SvnLogParam) (res HttpResponse, err error)
+	SvnLogList(param *SvnLogListParam) (res HttpResponse, err error)
+	SvnInfo(param *SvnInfoParam) (res HttpResponse, err error)
+	SvnCopyCreate(param *SvnCopyCreateParam) (res HttpResponse, err error) // async
+	SvnCopyList(param *SvnCopyListParam) (res HttpResponse, err error)
 	FtpLog(param *FtpLogParam) (res HttpResponse, err error)
 	FtpReadFile(param *FtpReadFileParam) (res HttpResponse, err error)
 	FtpWriteFile(param *FtpWriteFileParam) (res HttpResponse, err error)
 	FtpCompress(param *FtpCompressParam) (res HttpResponse, err error)
+	ReleaseChangelog(param *ReleaseChangelogParam) (res HttpResponse, err error)
 	TaskAll(param *TaskAllParam) (res HttpResponse, err error)
+	TaskList(param *TaskListParam) (res HttpResponse, err error)
+	TaskGet(param *TaskGetParam) (res HttpResponse, err error)
+	TaskCancel(param *TaskCancelParam) (res HttpResponse, err error)
+	TaskStream(param *TaskStreamParam) (history []string, ch <-chan string, unsubscribe func(), err error)
+	PipelineList(param *PipelineListParam) (res HttpResponse, err error)
+	PipelineRun(param *PipelineRunParam) (res HttpResponse, err error) // async
+	ScheduleList(param *ScheduleListParam) (res HttpResponse, err error)
+	SchedulePause(param *ScheduleParam) (res HttpResponse, err error)
+	ScheduleResume(param *ScheduleParam) (res HttpResponse, err error)
+	ScheduleTrigger(param *ScheduleParam) (res HttpResponse, err error) // async
 	OssEnvs(param *OssEnvsParam) (res HttpResponse, err error)
 	OssContent(param *OssContentParam) (res HttpResponse, err error)
 	OssUpdate(param *OssUpdateParam) (res HttpResponse, err error)
@@ -32,20 +63,50 @@ type Router interface {
 
 const (
 	RouteGetGitAll          = "/git/all"
+	RouteGitRefresh         = "/git/refresh/:projectName"
 	RouteGitGenerate        = "/git/gen/:projectName/:branchName"
 	RouteSetGitBranchSvnTag = "/git/set/:projectName/:branchName/:svnTag"
+	RouteGitLog             = "/git/log/:projectName/:branchName"
+	RouteGitSvnTagList      = "/git/svntag/list/:projectName"
+	RouteGitSvnTagSet       = "/git/svntag/set"
+	RouteGitSvnTagDelete    = "/git/svntag/delete"
+	RouteGitWebhook         = "/hooks/git/:projectName"
 	RouteSvnCommit          = "/svn/commit/:projectName/:branchName/:svnMsg"
+	RouteSvnPrepare         = "/svn/prepare"
+	RouteSvnPrepared        = "/svn/prepared/:projectName"
+	RouteSvnConfirm         = "/svn/confirm"
+	RouteSvnDiscard         = "/svn/discard"
+	RouteSvnRollback        = "/svn/rollback"
 	RouteSvnLog             = "/svn/log/:projectName/:logNumber"
+	RouteSvnLogList         = "/svn/log/:projectName"
+	RouteSvnInfo            = "/svn/info/:projectName"
+	RouteSvnCopyCreate      = "/svn/copy/create"
+	RouteSvnCopyList        = "/svn/copy/list/:projectName/:kind"
 	RouteFtpLog             = "/ftp/log/:projectName/:filter"
 	RouteFtpReadFile        = "/ftp/read/:projectName/:fileName"
 	RouteFtpWriteFile       = "/ftp/write"
 	RouteFtpCompress        = "/ftp/compress/:projectName/:branchName/:zipType/:zipFlags"
+	RouteReleaseChangelog   = "/release/changelog/:projectName/:branchName"
 	RouteTaskAll            = "/task/all/:projectName"
+	RouteTaskList           = "/task/list/:projectName"
+	RouteTaskGet            = "/task/:projectName/:taskId"
+	RouteTaskCancel         = "/task/cancel/:projectName/:taskId"
+	RouteTaskStream         = "/task/stream/:projectName/:taskId"
+	RoutePipelineList       = "/pipeline/list/:projectName"
+	RoutePipelineRun        = "/pipeline/run"
+	RouteScheduleList       = "/schedule/list/:projectName"
+	RouteSchedulePause      = "/schedule/pause/:projectName/:scheduleName"
+	RouteScheduleResume     = "/schedule/resume/:projectName/:scheduleName"
+	RouteScheduleTrigger    = "/schedule/trigger/:projectName/:scheduleName"
 	RouteOssEnvs            = "/oss/envs/:projectName"
 	RouteOssContent         = "/oss/content/:projectName/:env"
 	RouteOssUpdate          = "/oss/update"
 )
 
+const (
+	taskTimeLayout = "2006-01-02 15:04:05"
+)
+
 type router struct {
 	project operator.Project
 }
@@ -79,6 +140,20 @@ type CommonResponse struct {
 	}
 }
 
+// AsyncTaskResponse
+// swagger:response AsyncTaskResponse
+type AsyncTaskResponse struct {
+	// The task which was created for the async command
+	// in: body
+	Body struct {
+		SwaggerResponse
+		// The id of the task
+		//
+		// Required: true
+		TaskId int `json:"task_id"`
+	}
+}
+
 // GitAllResponse
 // swagger:response GitAllResponse
 type GitAllResponse struct {
@@ -111,6 +186,49 @@ func (r *router) GetGitAll() (res HttpResponse, err error) {
 	return GetQuickResponse(ret), nil
 }
 
+// swagger:parameters GitRefresh
+type GitRefreshParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: path
+	ProjectName string `json:"project_name"`
+}
+
+// GitRefreshResponse
+// swagger:response GitRefreshResponse
+type GitRefreshResponse struct {
+	// The git info
+	// in: body
+	Body struct {
+		SwaggerResponse
+		// The branches and the refresh status of the project, it was returned with the code CodeUnknownError if the refresh was failed
+		//
+		// Required: true
+		GitInfo operator.GitInfo `json:"git_info"`
+	}
+}
+
+// swagger:route POST /git/refresh/{projectName} git refresh GitRefresh
+//
+// It would fetch and list the branches of the specific project at once, and wait for the result
+//
+// git refresh
+//
+//     Responses:
+//       200: GitRefreshResponse
+func (r *router) GitRefresh(param *GitRefreshParam) (res HttpResponse, err error) {
+	ret, err := r.project.GitRefresh(param.ProjectName)
+	if err != nil {
+		klog.V(2).Infof("GitRefresh cmd:%v err:%v", *param, err)
+		if ret.Name == "" {
+			return res, err
+		}
+		return GetResponse(CodeUnknownError, err.Error(), ret), nil
+	}
+	return GetQuickResponse(ret), nil
+}
+
 // swagger:parameters genSpecificGit
 type GitGenerateParam struct {
 	// ProjectName
@@ -132,18 +250,19 @@ type GitGenerateParam struct {
 // generate and commit
 //
 //     Responses:
-//       200: CommonResponse
+//       200: AsyncTaskResponse
 func (r *router) GitGenerate(param *GitGenerateParam) (res HttpResponse, err error) {
 	c := &operator.Command{
 		ProjectName: param.ProjectName,
 		BranchName:  param.BranchName,
 		Command:     operator.TaskCmdGitGen,
 	}
-	if err := r.project.AsyncTask(c); err != nil {
+	res, err = GetAsyncTaskResponse(r.project.AsyncTask(c))
+	if err != nil {
 		klog.V(2).Infof("GitGenerate cmd:%v err:%v", *param, err)
 		return res, err
 	}
-	return GetQuickResponse(map[string]interface{}{}), nil
+	return res, nil
 }
 
 // swagger:parameters SetParam
@@ -182,8 +301,8 @@ func (r *router) SetGitBranchSvnTag(param *SetGitBranchSvnTagParam) (res HttpRes
 	return GetQuickResponse(map[string]interface{}{}), nil
 }
 
-// swagger:parameters SetSvnTag
-type SvnCommitParam struct {
+// swagger:parameters GitLog
+type GitLogParam struct {
 	// ProjectName
 	//
 	// Required: true
@@ -194,258 +313,268 @@ type SvnCommitParam struct {
 	// Required: true
 	// in: path
 	BranchName string `json:"branch_name"`
-	// SvnTag
+	// Limit, default: 20, max: 200
 	//
-	// Required: true
-	// in: path
-	SvnMessage string `json:"svn_message"`
+	// in: query
+	Limit int `json:"limit"`
 }
 
-// swagger:route GET /svn/commit/{projectName}/{branchName}/{svnMessage} svn commit SetSvnTag
+// GitLogResponse
+// swagger:response GitLogResponse
+type GitLogResponse struct {
+	// The commits
+	// in: body
+	Body struct {
+		SwaggerResponse
+		// The latest commits of the branch
+		//
+		// Required: true
+		Commits []operator.Commit `json:"commits"`
+	}
+}
+
+// swagger:route GET /git/log/{projectName}/{branchName} git log GitLog
 //
-// It would sync project files from the specific git.branch and commit to svn server
+// It would get the latest commits of the specific branch
 //
-// scn commit
+// git log
 //
 //     Responses:
-//       200: CommonResponse
-func (r *router) SvnCommit(param *SvnCommitParam) (res HttpResponse, err error) {
-	c := &operator.Command{
-		ProjectName: param.ProjectName,
-		BranchName:  param.BranchName,
-		Command:     operator.TaskCmdSvnCommit,
-		Message:     param.SvnMessage,
-	}
-	if err = r.project.AsyncTask(c); err != nil {
-		klog.V(2).Infof("SvnCommit cmd:%v err:%v", *param, err)
+//       200: GitLogResponse
+func (r *router) GitLog(param *GitLogParam) (res HttpResponse, err error) {
+	ret, err := r.project.GitLog(param.ProjectName, param.BranchName, param.Limit)
+	if err != nil {
+		klog.V(2).Infof("GitLog cmd:%v err:%v", *param, err)
 		return res, err
 	}
-	return GetQuickResponse(map[string]interface{}{}), nil
+	return GetQuickResponse(ret), nil
 }
 
-// swagger:parameters SvnLog
-type SvnLogParam struct {
+// swagger:parameters GitSvnTagList
+type GitSvnTagListParam struct {
 	// ProjectName
 	//
 	// Required: true
 	// in: path
 	ProjectName string `json:"project_name"`
-	// LogNumber
-	//
-	// Required: true
-	// in: path
-	LogNumber int `json:"log_number"`
 }
 
-// SvnLogResponse
-// swagger:response SvnLogResponse
-type SvnLogResponse struct {
-	// The svn logs
+// GitSvnTagListResponse
+// swagger:response GitSvnTagListResponse
+type GitSvnTagListResponse struct {
+	// The svn tags
 	// in: body
 	Body struct {
 		SwaggerResponse
-		// The set of svn logs
+		// The svn tags which were set to the branches, and the ones derived by the rules of the config
 		//
 		// Required: true
-		// An optional field name to which this validation applies
-		Logentrys []operator.Logentry `json:"logentrys"`
+		SvnTags []operator.SvnTagMapping `json:"svn_tags"`
 	}
 }
 
-// swagger:route GET /svn/log/{projectName}/{logNumber} svn log SvnLog
+// swagger:route GET /git/svntag/list/{projectName} git svntag GitSvnTagList
 //
-// It would pull svn logs from the remote svn server with the specific number
+// It would list the svn tags of the branches of the specific project
 //
-// svn log
+// git svntag list
 //
 //     Responses:
-//       200: SvnLogResponse
-func (r *router) SvnLog(param *SvnLogParam) (res HttpResponse, err error) {
-	ret, err := r.project.SvnLog(param.ProjectName, param.LogNumber)
+//       200: GitSvnTagListResponse
+func (r *router) GitSvnTagList(param *GitSvnTagListParam) (res HttpResponse, err error) {
+	ret, err := r.project.GitSvnTags(param.ProjectName)
 	if err != nil {
-		klog.V(2).Infof("SvnLog cmd:%v err:%v", *param, err)
+		klog.V(2).Infof("GitSvnTagList cmd:%v err:%v", *param, err)
 		return res, err
 	}
 	return GetQuickResponse(ret), nil
 }
 
-// swagger:parameters FtpLog
-type FtpLogParam struct {
+// swagger:parameters GitSvnTagSet GitSvnTagDelete
+type GitSvnTagParam struct {
 	// ProjectName
 	//
 	// Required: true
-	// in: path
-	ProjectName string `json:"project_name"`
-	// Filter
+	// in: formData
+	ProjectName string `json:"projectName"`
+	// BranchName, it could contain the slashes such as release/1.2
 	//
 	// Required: true
-	// in: path
-	Filter string `json:"filter"`
+	// in: formData
+	BranchName string `json:"branchName"`
+	// SvnTag, it would be ignored by the delete
+	//
+	// in: formData
+	SvnTag string `json:"svnTag"`
 }
 
-// FtpLogResponse
-// swagger:response FtpLogResponse
-type FtpLogResponse struct {
-	// The svn logs
-	// in: body
-	Body struct {
-		SwaggerResponse
-		// The list of ftp files
-		//
-		// Required: true
-		// An optional field name to which this validation applies
-		Entries []operator.Entry `json:"entries"`
+// swagger:route POST /git/svntag/set git svntag GitSvnTagSet
+//
+// It would set and persist the svn tag of the specific branch
+//
+// git svntag set
+//
+//     Responses:
+//       200: CommonResponse
+func (r *router) GitSvnTagSet(param *GitSvnTagParam) (res HttpResponse, err error) {
+	err = r.project.GitSetBranchSvnTag(param.ProjectName, param.BranchName, param.SvnTag)
+	if err != nil {
+		klog.V(2).Infof("GitSvnTagSet cmd:%v err:%v", *param, err)
+		return res, err
 	}
+	return GetQuickResponse(map[string]interface{}{}), nil
 }
 
-// swagger:route GET /ftp/log/{projectName}/{filter} ftp log FtpLog
+// swagger:route POST /git/svntag/delete git svntag GitSvnTagDelete
 //
-// It would pull ftp files from the remote ftp server with the specific filter
+// It would delete the svn tag of the specific branch, and the branch would fall back to the rules of the config
 //
-// ftp log
+// git svntag delete
 //
 //     Responses:
-//       200: FtpLogResponse
-func (r *router) FtpLog(param *FtpLogParam) (res HttpResponse, err error) {
-	ret, err := r.project.FtpLog(param.ProjectName, param.Filter)
+//       200: CommonResponse
+func (r *router) GitSvnTagDelete(param *GitSvnTagParam) (res HttpResponse, err error) {
+	err = r.project.GitDeleteBranchSvnTag(param.ProjectName, param.BranchName)
 	if err != nil {
-		klog.V(2).Infof("FtpLog cmd:%v err:%v", *param, err)
+		klog.V(2).Infof("GitSvnTagDelete cmd:%v err:%v", *param, err)
 		return res, err
 	}
-	return GetQuickResponse(ret), nil
+	return GetQuickResponse(map[string]interface{}{}), nil
 }
 
-// swagger:parameters FtpReadFile
-type FtpReadFileParam struct {
+// swagger:parameters GitWebhook
+type GitWebhookParam struct {
 	// ProjectName
 	//
 	// Required: true
 	// in: path
 	ProjectName string `json:"project_name"`
-	// FileName
+	// The headers carrying the secret token or the signature, and the event of the push
+	Header http.Header `json:"-"`
+	// The push payload of GitLab, GitHub or Gitea
 	//
-	// Required: true
-	// in: path
-	FileName string `json:"file_name"`
+	// in: body
+	Body []byte `json:"-"`
 }
 
-// FtpLogResponse
-// swagger:response FtpReadFileResponse
-type FtpReadFileResponse struct {
-	// The svn logs
+// GitWebhookResponse
+// swagger:response GitWebhookResponse
+type GitWebhookResponse struct {
+	// The enqueued tasks
 	// in: body
 	Body struct {
 		SwaggerResponse
-		// The file content
+		// The ids of the tasks enqueued by the rules matched by the pushed branch
 		//
 		// Required: true
-		// An optional field name to which this validation applies
-		Content string `json:"content"`
+		TaskIds []int `json:"task_ids"`
 	}
 }
 
-// swagger:route GET /ftp/read/{projectName}/{fileName} ftp read FtpReadFile
+// swagger:route POST /hooks/git/{projectName} git webhook GitWebhook
 //
-// It would get the content of the specific file from the remote ftp server by the filter
+// It would verify the push event of the git server, refresh the branches and enqueue the commands of the matched rules
 //
-// ftp read
+// git webhook
 //
 //     Responses:
-//       200: FtpReadFileResponse
-func (r *router) FtpReadFile(param *FtpReadFileParam) (res HttpResponse, err error) {
-	ret, err := r.project.FtpReadFile(param.ProjectName, param.FileName)
+//       200: GitWebhookResponse
+func (r *router) GitWebhook(param *GitWebhookParam) (res HttpResponse, err error) {
+	ids, err := r.project.GitWebhook(param.ProjectName, param.Header, param.Body)
 	if err != nil {
-		klog.V(2).Infof("FtpReadFile cmd:%v err:%v", *param, err)
+		klog.V(2).Infof("GitWebhook project:%s err:%v", param.ProjectName, err)
 		return res, err
 	}
-	return GetQuickResponse(string(ret)), nil
+	return GetQuickResponse(map[string]interface{}{"task_ids": ids}), nil
 }
 
-// swagger:parameters FtpWriteFile
-type FtpWriteFileParam struct {
+// swagger:parameters SetSvnTag
+type SvnCommitParam struct {
 	// ProjectName
 	//
 	// Required: true
 	// in: path
-	ProjectName string `json:"projectName"`
-	// FileName
+	ProjectName string `json:"project_name"`
+	// BranchName
 	//
 	// Required: true
 	// in: path
-	FileName string `json:"fileName"`
-	// Content
+	BranchName string `json:"branch_name"`
+	// SvnTag
 	//
 	// Required: true
 	// in: path
-	Content string `json:"content"`
+	SvnMessage string `json:"svn_message"`
 }
 
-// swagger:route POST /ftp/write ftp write FtpWriteFile
+// swagger:route GET /svn/commit/{projectName}/{branchName}/{svnMessage} svn commit SetSvnTag
 //
-// It would overwrite the specific file on the FTP server with the provided content
+// It would sync project files from the specific git.branch and commit to svn server
 //
-// ftp write
+// scn commit
 //
 //     Responses:
-//       200: CommonResponse
-func (r *router) FtpWriteFile(param *FtpWriteFileParam) (res HttpResponse, err error) {
-	err = r.project.FtpWriteFile(param.ProjectName, param.FileName, param.Content)
+//       200: AsyncTaskResponse
+func (r *router) SvnCommit(param *SvnCommitParam) (res HttpResponse, err error) {
+	c := &operator.Command{
+		ProjectName: param.ProjectName,
+		BranchName:  param.BranchName,
+		Command:     operator.TaskCmdSvnCommit,
+		Message:     param.SvnMessage,
+	}
+	res, err = GetAsyncTaskResponse(r.project.AsyncTask(c))
 	if err != nil {
-		klog.V(2).Infof("FtpWriteFile cmd:%v err:%v", *param, err)
+		klog.V(2).Infof("SvnCommit cmd:%v err:%v", *param, err)
 		return res, err
 	}
-	return GetQuickResponse(map[string]interface{}{}), nil
+	return res, nil
 }
 
-// swagger:parameters FtpCompress
-type FtpCompressParam struct {
+// swagger:parameters SvnPrepare
+type SvnPrepareParam struct {
 	// ProjectName
 	//
 	// Required: true
-	// in: path
-	ProjectName string `json:"project_name"`
+	// in: formData
+	ProjectName string `json:"projectName"`
 	// BranchName
 	//
 	// Required: true
-	// in: path
-	BranchName string `json:"branch_name"`
-	// ZipType
-	//
-	// Required: true
-	// in: path
-	ZipType string `json:"zip_type"`
-	// ZipFlags
+	// in: formData
+	BranchName string `json:"branchName"`
+	// SvnMessage would be used by the commit of the confirm
 	//
 	// Required: true
-	// in: path
-	ZipFlags string `json:"zip_flags"`
+	// in: formData
+	SvnMessage string `json:"svnMsg"`
 }
 
-// swagger:route GET /ftp/compress/{projectName}/{branchName}/{zipType}/{zipFlags} ftp compress FtpCompress
+// swagger:route POST /svn/prepare svn prepare SvnPrepare
 //
-// It would compress the project into zip with the specific flags and upload to the ftp server
+// It would synchronize the specific branch into the svn working copy without committing it,
+// the change set would be kept until it was confirmed or discarded
 //
-// ftp compress
+// svn prepare
 //
 //     Responses:
-//       200: CommonResponse
-func (r *router) FtpCompress(param *FtpCompressParam) (res HttpResponse, err error) {
+//       200: AsyncTaskResponse
+func (r *router) SvnPrepare(param *SvnPrepareParam) (res HttpResponse, err error) {
 	c := &operator.Command{
 		ProjectName: param.ProjectName,
 		BranchName:  param.BranchName,
-		Command:     operator.TaskCmdFtpUpload,
-		ZipType:     param.ZipType,
-		ZipFlags:    param.ZipFlags,
+		Command:     operator.TaskCmdSvnPrepare,
+		Message:     param.SvnMessage,
 	}
-	if err = r.project.AsyncTask(c); err != nil {
-		klog.V(2).Infof("FtpCompress cmd:%v err:%v", *param, err)
+	res, err = GetAsyncTaskResponse(r.project.AsyncTask(c))
+	if err != nil {
+		klog.V(2).Infof("SvnPrepare cmd:%v err:%v", *param, err)
 		return res, err
 	}
-	return GetQuickResponse(map[string]interface{}{}), nil
+	return res, nil
 }
 
-// swagger:parameters TaskAll
-type TaskAllParam struct {
+// swagger:parameters SvnPrepared
+type SvnPreparedParam struct {
 	// ProjectName
 	//
 	// Required: true
@@ -453,38 +582,1080 @@ type TaskAllParam struct {
 	ProjectName string `json:"project_name"`
 }
 
-// FtpLogResponse
-// swagger:response TaskAllResponse
-type TaskAllResponse struct {
-	// The svn logs
+// SvnPreparedResponse
+// swagger:response SvnPreparedResponse
+type SvnPreparedResponse struct {
+	// The prepared change set
 	// in: body
 	Body struct {
 		SwaggerResponse
-		// all tasks
+		// The change set waiting to be confirmed or discarded, it was null if there was none
 		//
 		// Required: true
-		// An optional field name to which this validation applies
-		Tasks map[int]operator.Task `json:"tasks"`
+		ChangeSet *operator.SvnChangeSet `json:"change_set"`
 	}
 }
 
-// swagger:route GET /task/all/{projectName} task all TaskAll
+// swagger:route GET /svn/prepared/{projectName} svn prepared SvnPrepared
 //
-// It would get all the tasks of the specific project
+// It would get the prepared change set of the specific project
 //
-// task all
+// svn prepared
 //
 //     Responses:
-//       200: TaskAllResponse
-func (r *router) TaskAll(param *TaskAllParam) (res HttpResponse, err error) {
-	ret, err := r.project.TaskAll(param.ProjectName)
+//       200: SvnPreparedResponse
+func (r *router) SvnPrepared(param *SvnPreparedParam) (res HttpResponse, err error) {
+	ret, err := r.project.SvnPrepared(param.ProjectName)
 	if err != nil {
-		klog.V(2).Infof("TaskAll cmd:%v err:%v", *param, err)
+		klog.V(2).Infof("SvnPrepared cmd:%v err:%v", *param, err)
 		return res, err
 	}
 	return GetQuickResponse(ret), nil
 }
 
+// swagger:parameters SvnConfirm SvnDiscard
+type SvnChangeSetParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: formData
+	ProjectName string `json:"projectName"`
+	// PrepareId is the id of the prepared change set
+	//
+	// Required: true
+	// in: formData
+	PrepareId int `json:"prepareId"`
+}
+
+// swagger:route POST /svn/confirm svn confirm SvnConfirm
+//
+// It would commit exactly the paths of the prepared change set by a task
+//
+// svn confirm
+//
+//     Responses:
+//       200: AsyncTaskResponse
+func (r *router) SvnConfirm(param *SvnChangeSetParam) (res HttpResponse, err error) {
+	c := &operator.Command{
+		ProjectName: param.ProjectName,
+		Command:     operator.TaskCmdSvnConfirm,
+		PrepareId:   param.PrepareId,
+	}
+	res, err = GetAsyncTaskResponse(r.project.AsyncTask(c))
+	if err != nil {
+		klog.V(2).Infof("SvnConfirm cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return res, nil
+}
+
+// swagger:route POST /svn/discard svn discard SvnDiscard
+//
+// It would clean the svn working copy and drop the prepared change set
+//
+// svn discard
+//
+//     Responses:
+//       200: CommonResponse
+func (r *router) SvnDiscard(param *SvnChangeSetParam) (res HttpResponse, err error) {
+	err = r.project.SvnDiscard(param.ProjectName, param.PrepareId)
+	if err != nil {
+		klog.V(2).Infof("SvnDiscard cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return GetQuickResponse(map[string]interface{}{}), nil
+}
+
+// swagger:parameters SvnRollback
+type SvnRollbackParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: formData
+	ProjectName string `json:"projectName"`
+	// Revision is the svn revision or the inclusive range (e.g. 120-125) to be rolled back
+	//
+	// Required: true
+	// in: formData
+	Revision string `json:"revision"`
+	// SvnMessage is the reason of the rollback, it would be committed after the rollback marker
+	//
+	// in: formData
+	SvnMessage string `json:"svnMsg"`
+}
+
+// swagger:route POST /svn/rollback svn rollback SvnRollback
+//
+// It would reverse-merge the specific revision or range at the head of the svn and commit it with the rollback marker
+//
+// svn rollback
+//
+//     Responses:
+//       200: AsyncTaskResponse
+func (r *router) SvnRollback(param *SvnRollbackParam) (res HttpResponse, err error) {
+	c := &operator.Command{
+		ProjectName: param.ProjectName,
+		Command:     operator.TaskCmdSvnRollback,
+		Message:     param.SvnMessage,
+		Revision:    param.Revision,
+	}
+	res, err = GetAsyncTaskResponse(r.project.AsyncTask(c))
+	if err != nil {
+		klog.V(2).Infof("SvnRollback cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return res, nil
+}
+
+// swagger:parameters SvnLog
+type SvnLogParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: path
+	ProjectName string `json:"project_name"`
+	// LogNumber
+	//
+	// Required: true
+	// in: path
+	LogNumber int `json:"log_number"`
+}
+
+// SvnLogResponse
+// swagger:response SvnLogResponse
+type SvnLogResponse struct {
+	// The svn logs
+	// in: body
+	Body struct {
+		SwaggerResponse
+		// The set of svn logs
+		//
+		// Required: true
+		// An optional field name to which this validation applies
+		Logentrys []operator.Logentry `json:"logentrys"`
+	}
+}
+
+// swagger:route GET /svn/log/{projectName}/{logNumber} svn log SvnLog
+//
+// It would pull svn logs from the remote svn server with the specific number
+//
+// svn log
+//
+//     Responses:
+//       200: SvnLogResponse
+func (r *router) SvnLog(param *SvnLogParam) (res HttpResponse, err error) {
+	ret, err := r.project.SvnLog(param.ProjectName, param.LogNumber)
+	if err != nil {
+		klog.V(2).Infof("SvnLog cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return GetQuickResponse(ret), nil
+}
+
+// swagger:parameters SvnLogList
+type SvnLogListParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: path
+	ProjectName string `json:"project_name"`
+	// Author
+	//
+	// in: query
+	Author string `json:"author"`
+	// Since, formatted as 2006-01-02 15:04:05
+	//
+	// in: query
+	Since string `json:"since"`
+	// Until, formatted as 2006-01-02 15:04:05
+	//
+	// in: query
+	Until string `json:"until"`
+	// From is the lowest revision, inclusive
+	//
+	// in: query
+	From int `json:"from"`
+	// To is the highest revision, inclusive
+	//
+	// in: query
+	To int `json:"to"`
+	// Message is a case-insensitive substring of the log message
+	//
+	// in: query
+	Message string `json:"message"`
+	// Path is a file or a dir relative to the working copy
+	//
+	// in: query
+	Path string `json:"path"`
+	// Before is the next of the previous page, only the revisions lower than it would be returned
+	//
+	// in: query
+	Before int `json:"before"`
+	// Limit, default: 20, max: 200
+	//
+	// in: query
+	Limit int `json:"limit"`
+}
+
+// SvnLogListResponse
+// swagger:response SvnLogListResponse
+type SvnLogListResponse struct {
+	// The svn logs
+	// in: body
+	Body struct {
+		SwaggerResponse
+		// The page of the matched svn logs
+		//
+		// Required: true
+		Page operator.SvnLogPage `json:"page"`
+	}
+}
+
+// swagger:route GET /svn/log/{projectName} svn log SvnLogList
+//
+// It would pull the svn logs matched by the filters page by page, the newest comes first
+//
+// svn log list
+//
+//     Responses:
+//       200: SvnLogListResponse
+func (r *router) SvnLogList(param *SvnLogListParam) (res HttpResponse, err error) {
+	f := operator.SvnLogFilter{
+		Author:       param.Author,
+		FromRevision: param.From,
+		ToRevision:   param.To,
+		Message:      param.Message,
+		Path:         param.Path,
+		Before:       param.Before,
+		Limit:        param.Limit,
+	}
+	if param.Since != "" {
+		if f.Since, err = time.ParseInLocation(taskTimeLayout, param.Since, time.Local); err != nil {
+			klog.V(2).Infof("SvnLogList cmd:%v err:%v", *param, err)
+			return res, err
+		}
+	}
+	if param.Until != "" {
+		if f.Until, err = time.ParseInLocation(taskTimeLayout, param.Until, time.Local); err != nil {
+			klog.V(2).Infof("SvnLogList cmd:%v err:%v", *param, err)
+			return res, err
+		}
+	}
+	ret, err := r.project.SvnLogList(param.ProjectName, f)
+	if err != nil {
+		klog.V(2).Infof("SvnLogList cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return GetQuickResponse(ret), nil
+}
+
+// swagger:parameters SvnInfo
+type SvnInfoParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: path
+	ProjectName string `json:"project_name"`
+}
+
+// SvnInfoResponse
+// swagger:response SvnInfoResponse
+type SvnInfoResponse struct {
+	// The svn info
+	// in: body
+	Body struct {
+		SwaggerResponse
+		// The info of the svn working copy and the results of its checkout and updates
+		//
+		// Required: true
+		Info operator.SvnInfo `json:"info"`
+	}
+}
+
+// swagger:route GET /svn/info/{projectName} svn info SvnInfo
+//
+// It would get the info and the health of the svn working copy of the specific project
+//
+// svn info
+//
+//     Responses:
+//       200: SvnInfoResponse
+func (r *router) SvnInfo(param *SvnInfoParam) (res HttpResponse, err error) {
+	ret, err := r.project.SvnInfo(param.ProjectName)
+	if err != nil {
+		klog.V(2).Infof("SvnInfo cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return GetQuickResponse(ret), nil
+}
+
+// svnCopyCommands are the task commands of the kinds of the svn copies
+var svnCopyCommands = map[string]string{
+	operator.SvnCopyTag:    operator.TaskCmdSvnTag,
+	operator.SvnCopyBranch: operator.TaskCmdSvnBranch,
+}
+
+// swagger:parameters SvnCopyCreate
+type SvnCopyCreateParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: formData
+	ProjectName string `json:"projectName"`
+	// Kind, tag or branch
+	//
+	// Required: true
+	// in: formData
+	Kind string `json:"kind"`
+	// Name of the tag or the branch, it would be rendered by the template of the config if it was empty
+	//
+	// in: formData
+	Name string `json:"name"`
+	// BranchName is the git branch which could be used by the template
+	//
+	// in: formData
+	BranchName string `json:"branchName"`
+	// SvnMessage
+	//
+	// in: formData
+	SvnMessage string `json:"svnMsg"`
+}
+
+// swagger:route POST /svn/copy/create svn copy SvnCopyCreate
+//
+// It would create the svn tag or branch by the server-side copy of the remote dir
+//
+// svn copy create
+//
+//     Responses:
+//       200: AsyncTaskResponse
+func (r *router) SvnCopyCreate(param *SvnCopyCreateParam) (res HttpResponse, err error) {
+	cmd, ok := svnCopyCommands[param.Kind]
+	if !ok {
+		err = errors.New(fmt.Sprintf("the kind `%s` of the svn copy is unknown", param.Kind))
+		klog.V(2).Infof("SvnCopyCreate cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	c := &operator.Command{
+		ProjectName: param.ProjectName,
+		BranchName:  param.BranchName,
+		Command:     cmd,
+		Message:     param.SvnMessage,
+		CopyName:    param.Name,
+	}
+	res, err = GetAsyncTaskResponse(r.project.AsyncTask(c))
+	if err != nil {
+		klog.V(2).Infof("SvnCopyCreate cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return res, nil
+}
+
+// swagger:parameters SvnCopyList
+type SvnCopyListParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: path
+	ProjectName string `json:"project_name"`
+	// Kind, tag or branch
+	//
+	// Required: true
+	// in: path
+	Kind string `json:"kind"`
+}
+
+// SvnCopyListResponse
+// swagger:response SvnCopyListResponse
+type SvnCopyListResponse struct {
+	// The svn copies
+	// in: body
+	Body struct {
+		SwaggerResponse
+		// The tags or the branches, the latest created one comes first
+		//
+		// Required: true
+		Copies []operator.SvnCopyEntry `json:"copies"`
+	}
+}
+
+// swagger:route GET /svn/copy/list/{projectName}/{kind} svn copy SvnCopyList
+//
+// It would list the svn tags or branches of the specific project
+//
+// svn copy list
+//
+//     Responses:
+//       200: SvnCopyListResponse
+func (r *router) SvnCopyList(param *SvnCopyListParam) (res HttpResponse, err error) {
+	ret, err := r.project.SvnCopies(param.ProjectName, param.Kind)
+	if err != nil {
+		klog.V(2).Infof("SvnCopyList cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return GetQuickResponse(ret), nil
+}
+
+// swagger:parameters FtpLog
+type FtpLogParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: path
+	ProjectName string `json:"project_name"`
+	// Filter
+	//
+	// Required: true
+	// in: path
+	Filter string `json:"filter"`
+}
+
+// FtpLogResponse
+// swagger:response FtpLogResponse
+type FtpLogResponse struct {
+	// The svn logs
+	// in: body
+	Body struct {
+		SwaggerResponse
+		// The list of ftp files
+		//
+		// Required: true
+		// An optional field name to which this validation applies
+		Entries []operator.Entry `json:"entries"`
+	}
+}
+
+// swagger:route GET /ftp/log/{projectName}/{filter} ftp log FtpLog
+//
+// It would pull ftp files from the remote ftp server with the specific filter
+//
+// ftp log
+//
+//     Responses:
+//       200: FtpLogResponse
+func (r *router) FtpLog(param *FtpLogParam) (res HttpResponse, err error) {
+	ret, err := r.project.FtpLog(param.ProjectName, param.Filter)
+	if err != nil {
+		klog.V(2).Infof("FtpLog cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return GetQuickResponse(ret), nil
+}
+
+// swagger:parameters FtpReadFile
+type FtpReadFileParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: path
+	ProjectName string `json:"project_name"`
+	// FileName
+	//
+	// Required: true
+	// in: path
+	FileName string `json:"file_name"`
+}
+
+// FtpLogResponse
+// swagger:response FtpReadFileResponse
+type FtpReadFileResponse struct {
+	// The svn logs
+	// in: body
+	Body struct {
+		SwaggerResponse
+		// The file content
+		//
+		// Required: true
+		// An optional field name to which this validation applies
+		Content string `json:"content"`
+	}
+}
+
+// swagger:route GET /ftp/read/{projectName}/{fileName} ftp read FtpReadFile
+//
+// It would get the content of the specific file from the remote ftp server by the filter
+//
+// ftp read
+//
+//     Responses:
+//       200: FtpReadFileResponse
+func (r *router) FtpReadFile(param *FtpReadFileParam) (res HttpResponse, err error) {
+	ret, err := r.project.FtpReadFile(param.ProjectName, param.FileName)
+	if err != nil {
+		klog.V(2).Infof("FtpReadFile cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return GetQuickResponse(string(ret)), nil
+}
+
+// swagger:parameters FtpWriteFile
+type FtpWriteFileParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: path
+	ProjectName string `json:"projectName"`
+	// FileName
+	//
+	// Required: true
+	// in: path
+	FileName string `json:"fileName"`
+	// Content
+	//
+	// Required: true
+	// in: path
+	Content string `json:"content"`
+}
+
+// swagger:route POST /ftp/write ftp write FtpWriteFile
+//
+// It would overwrite the specific file on the FTP server with the provided content
+//
+// ftp write
+//
+//     Responses:
+//       200: CommonResponse
+func (r *router) FtpWriteFile(param *FtpWriteFileParam) (res HttpResponse, err error) {
+	err = r.project.FtpWriteFile(param.ProjectName, param.FileName, param.Content)
+	if err != nil {
+		klog.V(2).Infof("FtpWriteFile cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return GetQuickResponse(map[string]interface{}{}), nil
+}
+
+// swagger:parameters FtpCompress
+type FtpCompressParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: path
+	ProjectName string `json:"project_name"`
+	// BranchName
+	//
+	// Required: true
+	// in: path
+	BranchName string `json:"branch_name"`
+	// ZipType
+	//
+	// Required: true
+	// in: path
+	ZipType string `json:"zip_type"`
+	// ZipFlags
+	//
+	// Required: true
+	// in: path
+	ZipFlags string `json:"zip_flags"`
+}
+
+// swagger:route GET /ftp/compress/{projectName}/{branchName}/{zipType}/{zipFlags} ftp compress FtpCompress
+//
+// It would compress the project into zip with the specific flags and upload to the ftp server
+//
+// ftp compress
+//
+//     Responses:
+//       200: AsyncTaskResponse
+func (r *router) FtpCompress(param *FtpCompressParam) (res HttpResponse, err error) {
+	c := &operator.Command{
+		ProjectName: param.ProjectName,
+		BranchName:  param.BranchName,
+		Command:     operator.TaskCmdFtpUpload,
+		ZipType:     param.ZipType,
+		ZipFlags:    param.ZipFlags,
+	}
+	res, err = GetAsyncTaskResponse(r.project.AsyncTask(c))
+	if err != nil {
+		klog.V(2).Infof("FtpCompress cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return res, nil
+}
+
+// swagger:parameters ReleaseChangelog
+type ReleaseChangelogParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: path
+	ProjectName string `json:"project_name"`
+	// BranchName
+	//
+	// Required: true
+	// in: path
+	BranchName string `json:"branch_name"`
+}
+
+// ReleaseChangelogResponse
+// swagger:response ReleaseChangelogResponse
+type ReleaseChangelogResponse struct {
+	// The changelog
+	// in: body
+	Body struct {
+		SwaggerResponse
+		// The commits and the revisions since the last release of the branch, and the rendered content
+		//
+		// Required: true
+		Data operator.Changelog `json:"data"`
+	}
+}
+
+// swagger:route GET /release/changelog/{projectName}/{branchName} release changelog ReleaseChangelog
+//
+// It would get the changelog of the specific branch since its last ftp publish
+//
+// release changelog
+//
+//     Responses:
+//       200: ReleaseChangelogResponse
+func (r *router) ReleaseChangelog(param *ReleaseChangelogParam) (res HttpResponse, err error) {
+	ret, err := r.project.ReleaseChangelog(param.ProjectName, param.BranchName)
+	if err != nil {
+		klog.V(2).Infof("ReleaseChangelog cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return GetQuickResponse(ret), nil
+}
+
+// swagger:parameters TaskAll
+type TaskAllParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: path
+	ProjectName string `json:"project_name"`
+}
+
+// FtpLogResponse
+// swagger:response TaskAllResponse
+type TaskAllResponse struct {
+	// The svn logs
+	// in: body
+	Body struct {
+		SwaggerResponse
+		// all tasks
+		//
+		// Required: true
+		// An optional field name to which this validation applies
+		Tasks map[int]operator.Task `json:"tasks"`
+	}
+}
+
+// swagger:route GET /task/all/{projectName} task all TaskAll
+//
+// It would get all the tasks of the specific project, use TaskList for the paginated tasks instead
+//
+// task all
+//
+//     Responses:
+//       200: TaskAllResponse
+func (r *router) TaskAll(param *TaskAllParam) (res HttpResponse, err error) {
+	ret, err := r.project.TaskAll(param.ProjectName)
+	if err != nil {
+		klog.V(2).Infof("TaskAll cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return GetQuickResponse(ret), nil
+}
+
+// swagger:parameters TaskList
+type TaskListParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: path
+	ProjectName string `json:"project_name"`
+	// Status, such as waiting, processing, completed, error, interrupted and cancelled
+	//
+	// in: query
+	Status string `json:"status"`
+	// Command
+	//
+	// in: query
+	Command string `json:"command"`
+	// BranchName
+	//
+	// in: query
+	BranchName string `json:"branch"`
+	// Since, the tasks created before it would be filtered, format: 2006-01-02 15:04:05
+	//
+	// in: query
+	Since string `json:"since"`
+	// Until, the tasks created after it would be filtered, format: 2006-01-02 15:04:05
+	//
+	// in: query
+	Until string `json:"until"`
+	// Page, start from 1
+	//
+	// in: query
+	Page int `json:"page"`
+	// PageSize, default: 20, max: 200
+	//
+	// in: query
+	PageSize int `json:"page_size"`
+}
+
+// TaskListResponse
+// swagger:response TaskListResponse
+type TaskListResponse struct {
+	// The page of the tasks
+	// in: body
+	Body struct {
+		SwaggerResponse
+		// The tasks sorted by the id in descending order
+		//
+		// Required: true
+		Data operator.TaskPage `json:"data"`
+	}
+}
+
+// swagger:route GET /task/list/{projectName} task list TaskList
+//
+// It would list the tasks of the specific project page by page with the filters
+//
+// task list
+//
+//     Responses:
+//       200: TaskListResponse
+func (r *router) TaskList(param *TaskListParam) (res HttpResponse, err error) {
+	f := operator.TaskFilter{
+		Status:     param.Status,
+		Command:    param.Command,
+		BranchName: param.BranchName,
+		Page:       param.Page,
+		PageSize:   param.PageSize,
+	}
+	if param.Since != "" {
+		if f.Since, err = time.ParseInLocation(taskTimeLayout, param.Since, time.Local); err != nil {
+			klog.V(2).Infof("TaskList cmd:%v err:%v", *param, err)
+			return res, err
+		}
+	}
+	if param.Until != "" {
+		if f.Until, err = time.ParseInLocation(taskTimeLayout, param.Until, time.Local); err != nil {
+			klog.V(2).Infof("TaskList cmd:%v err:%v", *param, err)
+			return res, err
+		}
+	}
+	ret, err := r.project.TaskList(param.ProjectName, f)
+	if err != nil {
+		klog.V(2).Infof("TaskList cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return GetQuickResponse(ret), nil
+}
+
+// swagger:parameters TaskGet
+type TaskGetParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: path
+	ProjectName string `json:"project_name"`
+	// TaskId
+	//
+	// Required: true
+	// in: path
+	TaskId int `json:"task_id"`
+}
+
+// TaskGetResponse
+// swagger:response TaskGetResponse
+type TaskGetResponse struct {
+	// The task
+	// in: body
+	Body struct {
+		SwaggerResponse
+		// The task with the timestamps, the attempts and the executed scripts with their outputs
+		//
+		// Required: true
+		Data operator.Task `json:"data"`
+	}
+}
+
+// swagger:route GET /task/{projectName}/{taskId} task get TaskGet
+//
+// It would get the detail of the specific task
+//
+// task get
+//
+//     Responses:
+//       200: TaskGetResponse
+func (r *router) TaskGet(param *TaskGetParam) (res HttpResponse, err error) {
+	ret, err := r.project.TaskGet(param.ProjectName, param.TaskId)
+	if err != nil {
+		klog.V(2).Infof("TaskGet cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return GetQuickResponse(ret), nil
+}
+
+// swagger:parameters TaskCancel
+type TaskCancelParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: path
+	ProjectName string `json:"project_name"`
+	// TaskId
+	//
+	// Required: true
+	// in: path
+	TaskId int `json:"task_id"`
+}
+
+// swagger:route POST /task/cancel/{projectName}/{taskId} task cancel TaskCancel
+//
+// It would cancel the specific task, a waiting task would never be run and the processes of a processing one would be killed
+//
+// task cancel
+//
+//     Responses:
+//       200: CommonResponse
+func (r *router) TaskCancel(param *TaskCancelParam) (res HttpResponse, err error) {
+	err = r.project.TaskCancel(param.ProjectName, param.TaskId)
+	if err != nil {
+		klog.V(2).Infof("TaskCancel cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return GetQuickResponse(map[string]interface{}{}), nil
+}
+
+// swagger:parameters TaskStream
+type TaskStreamParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: path
+	ProjectName string `json:"project_name"`
+	// TaskId
+	//
+	// Required: true
+	// in: path
+	TaskId int `json:"task_id"`
+}
+
+// swagger:route GET /task/stream/{projectName}/{taskId} task stream TaskStream
+//
+// It would push the messages of the specific task as Server-Sent Events,
+// the history messages come first and the stream ends with an `end` event after the task was finished
+//
+// task stream
+//
+//     Produces:
+//     - text/event-stream
+func (r *router) TaskStream(param *TaskStreamParam) (history []string, ch <-chan string, unsubscribe func(), err error) {
+	history, ch, unsubscribe, err = r.project.TaskSubscribe(param.ProjectName, param.TaskId)
+	if err != nil {
+		klog.V(2).Infof("TaskStream cmd:%v err:%v", *param, err)
+		return history, ch, unsubscribe, err
+	}
+	return history, ch, unsubscribe, nil
+}
+
+// swagger:parameters PipelineList
+type PipelineListParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: path
+	ProjectName string `json:"project_name"`
+}
+
+// PipelineListResponse
+// swagger:response PipelineListResponse
+type PipelineListResponse struct {
+	// The pipelines
+	// in: body
+	Body struct {
+		SwaggerResponse
+		// The pipelines declared in the config of the project
+		//
+		// Required: true
+		Pipelines []operator.PipelineConfig `json:"pipelines"`
+	}
+}
+
+// swagger:route GET /pipeline/list/{projectName} pipeline list PipelineList
+//
+// It would list the pipelines of the specific project
+//
+// pipeline list
+//
+//     Responses:
+//       200: PipelineListResponse
+func (r *router) PipelineList(param *PipelineListParam) (res HttpResponse, err error) {
+	ret, err := r.project.PipelineList(param.ProjectName)
+	if err != nil {
+		klog.V(2).Infof("PipelineList cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return GetQuickResponse(ret), nil
+}
+
+// swagger:parameters PipelineRun
+type PipelineRunParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: formData
+	ProjectName string `json:"projectName"`
+	// PipelineName
+	//
+	// Required: true
+	// in: formData
+	PipelineName string `json:"pipelineName"`
+	// BranchName
+	//
+	// Required: true
+	// in: formData
+	BranchName string `json:"branchName"`
+	// Message is the svn message shared by the steps
+	//
+	// in: formData
+	Message string `json:"message"`
+	// ZipType is shared by the steps
+	//
+	// in: formData
+	ZipType string `json:"zipType"`
+	// ZipFlags is shared by the steps
+	//
+	// in: formData
+	ZipFlags string `json:"zipFlags"`
+}
+
+// swagger:route POST /pipeline/run pipeline run PipelineRun
+//
+// It would run the steps of the specific pipeline one by one, each step would be a child task of the pipeline task
+//
+// pipeline run
+//
+//     Responses:
+//       200: AsyncTaskResponse
+func (r *router) PipelineRun(param *PipelineRunParam) (res HttpResponse, err error) {
+	c := &operator.Command{
+		ProjectName: param.ProjectName,
+		BranchName:  param.BranchName,
+		Command:     operator.TaskCmdPipeline,
+		Message:     param.Message,
+		ZipType:     param.ZipType,
+		ZipFlags:    param.ZipFlags,
+		Pipeline:    param.PipelineName,
+	}
+	res, err = GetAsyncTaskResponse(r.project.AsyncTask(c))
+	if err != nil {
+		klog.V(2).Infof("PipelineRun cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return res, nil
+}
+
+// swagger:parameters ScheduleList
+type ScheduleListParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: path
+	ProjectName string `json:"project_name"`
+}
+
+// ScheduleListResponse
+// swagger:response ScheduleListResponse
+type ScheduleListResponse struct {
+	// The schedules
+	// in: body
+	Body struct {
+		SwaggerResponse
+		// The schedules of the project with their states
+		//
+		// Required: true
+		Schedules []operator.Schedule `json:"schedules"`
+	}
+}
+
+// swagger:route GET /schedule/list/{projectName} schedule list ScheduleList
+//
+// It would list the schedules of the specific project
+//
+// schedule list
+//
+//     Responses:
+//       200: ScheduleListResponse
+func (r *router) ScheduleList(param *ScheduleListParam) (res HttpResponse, err error) {
+	ret, err := r.project.ScheduleList(param.ProjectName)
+	if err != nil {
+		klog.V(2).Infof("ScheduleList cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return GetQuickResponse(ret), nil
+}
+
+// swagger:parameters SchedulePause ScheduleResume ScheduleTrigger
+type ScheduleParam struct {
+	// ProjectName
+	//
+	// Required: true
+	// in: path
+	ProjectName string `json:"project_name"`
+	// ScheduleName
+	//
+	// Required: true
+	// in: path
+	ScheduleName string `json:"schedule_name"`
+}
+
+// swagger:route POST /schedule/pause/{projectName}/{scheduleName} schedule pause SchedulePause
+//
+// It would pause the specific schedule until it was resumed
+//
+// schedule pause
+//
+//     Responses:
+//       200: CommonResponse
+func (r *router) SchedulePause(param *ScheduleParam) (res HttpResponse, err error) {
+	err = r.project.SchedulePause(param.ProjectName, param.ScheduleName)
+	if err != nil {
+		klog.V(2).Infof("SchedulePause cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return GetQuickResponse(map[string]interface{}{}), nil
+}
+
+// swagger:route POST /schedule/resume/{projectName}/{scheduleName} schedule resume ScheduleResume
+//
+// It would resume the specific schedule
+//
+// schedule resume
+//
+//     Responses:
+//       200: CommonResponse
+func (r *router) ScheduleResume(param *ScheduleParam) (res HttpResponse, err error) {
+	err = r.project.ScheduleResume(param.ProjectName, param.ScheduleName)
+	if err != nil {
+		klog.V(2).Infof("ScheduleResume cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return GetQuickResponse(map[string]interface{}{}), nil
+}
+
+// swagger:route POST /schedule/trigger/{projectName}/{scheduleName} schedule trigger ScheduleTrigger
+//
+// It would enqueue the command of the specific schedule now, unless the previous task of it was still pending
+//
+// schedule trigger
+//
+//     Responses:
+//       200: AsyncTaskResponse
+func (r *router) ScheduleTrigger(param *ScheduleParam) (res HttpResponse, err error) {
+	res, err = GetAsyncTaskResponse(r.project.ScheduleTrigger(param.ProjectName, param.ScheduleName))
+	if err != nil {
+		klog.V(2).Infof("ScheduleTrigger cmd:%v err:%v", *param, err)
+		return res, err
+	}
+	return res, nil
+}
+
 func NewRouter(p operator.Project) Router {
 	var r Router = &router{
 		project: p,